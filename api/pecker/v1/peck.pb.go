@@ -25,31 +25,74 @@ type PeckRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PlanId              uint64            `protobuf:"varint,1,opt,name=planId,proto3" json:"planId,omitempty"`
-	TaskId              uint64            `protobuf:"varint,2,opt,name=taskId,proto3" json:"taskId,omitempty"`
-	StressType          int32             `protobuf:"varint,3,opt,name=stressType,proto3" json:"stressType,omitempty"`
-	StressMode          int32             `protobuf:"varint,35,opt,name=stressMode,proto3" json:"stressMode,omitempty"`
-	Num                 int32             `protobuf:"varint,4,opt,name=num,proto3" json:"num,omitempty"`
-	StepIntervalTime    int32             `protobuf:"varint,32,opt,name=stepIntervalTime,proto3" json:"stepIntervalTime,omitempty"`
-	Nums                []int32           `protobuf:"varint,33,rep,packed,name=nums,proto3" json:"nums,omitempty"`
-	Addr                string            `protobuf:"bytes,34,opt,name=addr,proto3" json:"addr,omitempty"`
-	MaxConnections      int32             `protobuf:"varint,6,opt,name=maxConnections,proto3" json:"maxConnections,omitempty"`
-	MaxIdleConnections  int32             `protobuf:"varint,7,opt,name=maxIdleConnections,proto3" json:"maxIdleConnections,omitempty"`
-	StressTime          int32             `protobuf:"varint,8,opt,name=stressTime,proto3" json:"stressTime,omitempty"`
-	Timeout             int32             `protobuf:"varint,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Url                 string            `protobuf:"bytes,10,opt,name=url,proto3" json:"url,omitempty"`
-	Method              string            `protobuf:"bytes,11,opt,name=method,proto3" json:"method,omitempty"`
-	Headers             map[string]string `protobuf:"bytes,12,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Query               string            `protobuf:"bytes,13,opt,name=query,proto3" json:"query,omitempty"`
-	Body                string            `protobuf:"bytes,14,opt,name=body,proto3" json:"body,omitempty"`
-	DynamicParams       []*DynamicParam   `protobuf:"bytes,15,rep,name=dynamicParams,proto3" json:"dynamicParams,omitempty"`
-	ResponseCheckScript string            `protobuf:"bytes,16,opt,name=responseCheckScript,proto3" json:"responseCheckScript,omitempty"`
-	DisableKeepAlive    bool              `protobuf:"varint,17,opt,name=disableKeepAlive,proto3" json:"disableKeepAlive,omitempty"`
-	H2                  bool              `protobuf:"varint,18,opt,name=h2,proto3" json:"h2,omitempty"`
-	MaxBodySize         int32             `protobuf:"varint,19,opt,name=maxBodySize,proto3" json:"maxBodySize,omitempty"`
-	DisableCompression  bool              `protobuf:"varint,20,opt,name=disableCompression,proto3" json:"disableCompression,omitempty"`
-	DisableRedirects    bool              `protobuf:"varint,21,opt,name=disableRedirects,proto3" json:"disableRedirects,omitempty"`
-	Proxy               string            `protobuf:"bytes,22,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	PlanId               uint64            `protobuf:"varint,1,opt,name=planId,proto3" json:"planId,omitempty"`
+	TaskId               uint64            `protobuf:"varint,2,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	StressType           int32             `protobuf:"varint,3,opt,name=stressType,proto3" json:"stressType,omitempty"`
+	StressMode           int32             `protobuf:"varint,35,opt,name=stressMode,proto3" json:"stressMode,omitempty"`
+	Num                  int32             `protobuf:"varint,4,opt,name=num,proto3" json:"num,omitempty"`
+	StepIntervalTime     int32             `protobuf:"varint,32,opt,name=stepIntervalTime,proto3" json:"stepIntervalTime,omitempty"`
+	Nums                 []int32           `protobuf:"varint,33,rep,packed,name=nums,proto3" json:"nums,omitempty"`
+	Addr                 string            `protobuf:"bytes,34,opt,name=addr,proto3" json:"addr,omitempty"`
+	MaxConnections       int32             `protobuf:"varint,6,opt,name=maxConnections,proto3" json:"maxConnections,omitempty"`
+	MaxIdleConnections   int32             `protobuf:"varint,7,opt,name=maxIdleConnections,proto3" json:"maxIdleConnections,omitempty"`
+	StressTime           int32             `protobuf:"varint,8,opt,name=stressTime,proto3" json:"stressTime,omitempty"`
+	Timeout              int32             `protobuf:"varint,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Url                  string            `protobuf:"bytes,10,opt,name=url,proto3" json:"url,omitempty"`
+	Method               string            `protobuf:"bytes,11,opt,name=method,proto3" json:"method,omitempty"`
+	Headers              map[string]string `protobuf:"bytes,12,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Query                string            `protobuf:"bytes,13,opt,name=query,proto3" json:"query,omitempty"`
+	Body                 string            `protobuf:"bytes,14,opt,name=body,proto3" json:"body,omitempty"`
+	DynamicParams        []*DynamicParam   `protobuf:"bytes,15,rep,name=dynamicParams,proto3" json:"dynamicParams,omitempty"`
+	ResponseCheckScript  string            `protobuf:"bytes,16,opt,name=responseCheckScript,proto3" json:"responseCheckScript,omitempty"`
+	DisableKeepAlive     bool              `protobuf:"varint,17,opt,name=disableKeepAlive,proto3" json:"disableKeepAlive,omitempty"`
+	H2                   bool              `protobuf:"varint,18,opt,name=h2,proto3" json:"h2,omitempty"`
+	MaxBodySize          int32             `protobuf:"varint,19,opt,name=maxBodySize,proto3" json:"maxBodySize,omitempty"`
+	DisableCompression   bool              `protobuf:"varint,20,opt,name=disableCompression,proto3" json:"disableCompression,omitempty"`
+	DisableRedirects     bool              `protobuf:"varint,21,opt,name=disableRedirects,proto3" json:"disableRedirects,omitempty"`
+	Proxy                string            `protobuf:"bytes,22,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	ProtocolType         int32             `protobuf:"varint,36,opt,name=protocolType,proto3" json:"protocolType,omitempty"`
+	GrpcMethod           string            `protobuf:"bytes,37,opt,name=grpcMethod,proto3" json:"grpcMethod,omitempty"`
+	ProtoDescriptor      string            `protobuf:"bytes,38,opt,name=protoDescriptor,proto3" json:"protoDescriptor,omitempty"`
+	WsMessages           string            `protobuf:"bytes,39,opt,name=wsMessages,proto3" json:"wsMessages,omitempty"`
+	WsCorrelationField   string            `protobuf:"bytes,40,opt,name=wsCorrelationField,proto3" json:"wsCorrelationField,omitempty"`
+	WsHoldTime           int32             `protobuf:"varint,41,opt,name=wsHoldTime,proto3" json:"wsHoldTime,omitempty"`
+	StreamType           int32             `protobuf:"varint,42,opt,name=streamType,proto3" json:"streamType,omitempty"`
+	PayloadEncoding      int32             `protobuf:"varint,43,opt,name=payloadEncoding,proto3" json:"payloadEncoding,omitempty"`
+	ResponseDelimiter    string            `protobuf:"bytes,44,opt,name=responseDelimiter,proto3" json:"responseDelimiter,omitempty"`
+	ResponseLength       int32             `protobuf:"varint,45,opt,name=responseLength,proto3" json:"responseLength,omitempty"`
+	DnsQueryType         string            `protobuf:"bytes,46,opt,name=dnsQueryType,proto3" json:"dnsQueryType,omitempty"`
+	RedisCommands        string            `protobuf:"bytes,47,opt,name=redisCommands,proto3" json:"redisCommands,omitempty"`
+	ScenarioSteps        string            `protobuf:"bytes,48,opt,name=scenarioSteps,proto3" json:"scenarioSteps,omitempty"`
+	ThinkTimeType        int32             `protobuf:"varint,49,opt,name=thinkTimeType,proto3" json:"thinkTimeType,omitempty"`
+	ThinkTime            int32             `protobuf:"varint,50,opt,name=thinkTime,proto3" json:"thinkTime,omitempty"`
+	ThinkTimeMax         int32             `protobuf:"varint,51,opt,name=thinkTimeMax,proto3" json:"thinkTimeMax,omitempty"`
+	PacingTime           int32             `protobuf:"varint,52,opt,name=pacingTime,proto3" json:"pacingTime,omitempty"`
+	PacerType            int32             `protobuf:"varint,53,opt,name=pacerType,proto3" json:"pacerType,omitempty"`
+	PacerStartNum        int32             `protobuf:"varint,54,opt,name=pacerStartNum,proto3" json:"pacerStartNum,omitempty"`
+	PacerEndNum          int32             `protobuf:"varint,55,opt,name=pacerEndNum,proto3" json:"pacerEndNum,omitempty"`
+	PacerPeriod          int32             `protobuf:"varint,56,opt,name=pacerPeriod,proto3" json:"pacerPeriod,omitempty"`
+	PacerSteps           int32             `protobuf:"varint,57,opt,name=pacerSteps,proto3" json:"pacerSteps,omitempty"`
+	StageDurations       []int32           `protobuf:"varint,58,rep,packed,name=stageDurations,proto3" json:"stageDurations,omitempty"`
+	StageTransitions     []int32           `protobuf:"varint,59,rep,packed,name=stageTransitions,proto3" json:"stageTransitions,omitempty"`
+	MaxInFlight          int32             `protobuf:"varint,60,opt,name=maxInFlight,proto3" json:"maxInFlight,omitempty"`
+	InFlightPolicy       int32             `protobuf:"varint,61,opt,name=inFlightPolicy,proto3" json:"inFlightPolicy,omitempty"`
+	InFlightQueueTimeout int32             `protobuf:"varint,62,opt,name=inFlightQueueTimeout,proto3" json:"inFlightQueueTimeout,omitempty"`
+	FastHttp             bool              `protobuf:"varint,63,opt,name=fastHttp,proto3" json:"fastHttp,omitempty"`
+	BodyMode             int32             `protobuf:"varint,64,opt,name=bodyMode,proto3" json:"bodyMode,omitempty"`
+	BodyChecksum         string            `protobuf:"bytes,65,opt,name=bodyChecksum,proto3" json:"bodyChecksum,omitempty"`
+	TlsVerify            bool              `protobuf:"varint,66,opt,name=tlsVerify,proto3" json:"tlsVerify,omitempty"`
+	TlsCa                string            `protobuf:"bytes,67,opt,name=tlsCa,proto3" json:"tlsCa,omitempty"`
+	TlsCert              string            `protobuf:"bytes,68,opt,name=tlsCert,proto3" json:"tlsCert,omitempty"`
+	TlsKey               string            `protobuf:"bytes,69,opt,name=tlsKey,proto3" json:"tlsKey,omitempty"`
+	TlsServerName        string            `protobuf:"bytes,70,opt,name=tlsServerName,proto3" json:"tlsServerName,omitempty"`
+	TlsMinVersion        string            `protobuf:"bytes,71,opt,name=tlsMinVersion,proto3" json:"tlsMinVersion,omitempty"`
+	TlsMaxVersion        string            `protobuf:"bytes,72,opt,name=tlsMaxVersion,proto3" json:"tlsMaxVersion,omitempty"`
+	TlsCipherSuites      string            `protobuf:"bytes,73,opt,name=tlsCipherSuites,proto3" json:"tlsCipherSuites,omitempty"`
+	TlsAlpn              string            `protobuf:"bytes,74,opt,name=tlsAlpn,proto3" json:"tlsAlpn,omitempty"`
+	TlsFreshHandshake    bool              `protobuf:"varint,75,opt,name=tlsFreshHandshake,proto3" json:"tlsFreshHandshake,omitempty"`
+	HostMapping          string            `protobuf:"bytes,76,opt,name=hostMapping,proto3" json:"hostMapping,omitempty"`
+	DnsCacheTtl          int32             `protobuf:"varint,77,opt,name=dnsCacheTtl,proto3" json:"dnsCacheTtl,omitempty"`
+	SourceAddresses      string            `protobuf:"bytes,78,opt,name=sourceAddresses,proto3" json:"sourceAddresses,omitempty"`
 }
 
 func (x *PeckRequest) Reset() {
@@ -259,6 +302,307 @@ func (x *PeckRequest) GetProxy() string {
 	return ""
 }
 
+func (x *PeckRequest) GetProtocolType() int32 {
+	if x != nil {
+		return x.ProtocolType
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetGrpcMethod() string {
+	if x != nil {
+		return x.GrpcMethod
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetProtoDescriptor() string {
+	if x != nil {
+		return x.ProtoDescriptor
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetWsMessages() string {
+	if x != nil {
+		return x.WsMessages
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetWsCorrelationField() string {
+	if x != nil {
+		return x.WsCorrelationField
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetWsHoldTime() int32 {
+	if x != nil {
+		return x.WsHoldTime
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetStreamType() int32 {
+	if x != nil {
+		return x.StreamType
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPayloadEncoding() int32 {
+	if x != nil {
+		return x.PayloadEncoding
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetResponseDelimiter() string {
+	if x != nil {
+		return x.ResponseDelimiter
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetResponseLength() int32 {
+	if x != nil {
+		return x.ResponseLength
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetDnsQueryType() string {
+	if x != nil {
+		return x.DnsQueryType
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetRedisCommands() string {
+	if x != nil {
+		return x.RedisCommands
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetScenarioSteps() string {
+	if x != nil {
+		return x.ScenarioSteps
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetThinkTimeType() int32 {
+	if x != nil {
+		return x.ThinkTimeType
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetThinkTime() int32 {
+	if x != nil {
+		return x.ThinkTime
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetThinkTimeMax() int32 {
+	if x != nil {
+		return x.ThinkTimeMax
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPacingTime() int32 {
+	if x != nil {
+		return x.PacingTime
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPacerType() int32 {
+	if x != nil {
+		return x.PacerType
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPacerStartNum() int32 {
+	if x != nil {
+		return x.PacerStartNum
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPacerEndNum() int32 {
+	if x != nil {
+		return x.PacerEndNum
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPacerPeriod() int32 {
+	if x != nil {
+		return x.PacerPeriod
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetPacerSteps() int32 {
+	if x != nil {
+		return x.PacerSteps
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetStageDurations() []int32 {
+	if x != nil {
+		return x.StageDurations
+	}
+	return nil
+}
+
+func (x *PeckRequest) GetStageTransitions() []int32 {
+	if x != nil {
+		return x.StageTransitions
+	}
+	return nil
+}
+
+func (x *PeckRequest) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetInFlightPolicy() int32 {
+	if x != nil {
+		return x.InFlightPolicy
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetInFlightQueueTimeout() int32 {
+	if x != nil {
+		return x.InFlightQueueTimeout
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetFastHttp() bool {
+	if x != nil {
+		return x.FastHttp
+	}
+	return false
+}
+
+func (x *PeckRequest) GetBodyMode() int32 {
+	if x != nil {
+		return x.BodyMode
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetBodyChecksum() string {
+	if x != nil {
+		return x.BodyChecksum
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsVerify() bool {
+	if x != nil {
+		return x.TlsVerify
+	}
+	return false
+}
+
+func (x *PeckRequest) GetTlsCa() string {
+	if x != nil {
+		return x.TlsCa
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsCert() string {
+	if x != nil {
+		return x.TlsCert
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsKey() string {
+	if x != nil {
+		return x.TlsKey
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsServerName() string {
+	if x != nil {
+		return x.TlsServerName
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsMinVersion() string {
+	if x != nil {
+		return x.TlsMinVersion
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsMaxVersion() string {
+	if x != nil {
+		return x.TlsMaxVersion
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsCipherSuites() string {
+	if x != nil {
+		return x.TlsCipherSuites
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsAlpn() string {
+	if x != nil {
+		return x.TlsAlpn
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetTlsFreshHandshake() bool {
+	if x != nil {
+		return x.TlsFreshHandshake
+	}
+	return false
+}
+
+func (x *PeckRequest) GetHostMapping() string {
+	if x != nil {
+		return x.HostMapping
+	}
+	return ""
+}
+
+func (x *PeckRequest) GetDnsCacheTtl() int32 {
+	if x != nil {
+		return x.DnsCacheTtl
+	}
+	return 0
+}
+
+func (x *PeckRequest) GetSourceAddresses() string {
+	if x != nil {
+		return x.SourceAddresses
+	}
+	return ""
+}
+
 type DynamicParam struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -476,7 +820,7 @@ var File_api_pecker_v1_peck_proto protoreflect.FileDescriptor
 var file_api_pecker_v1_peck_proto_rawDesc = []byte{
 	0x0a, 0x18, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f,
 	0x70, 0x65, 0x63, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x70, 0x65, 0x63, 0x6b,
-	0x65, 0x72, 0x22, 0xff, 0x06, 0x0a, 0x0b, 0x50, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x65, 0x72, 0x22, 0x83, 0x13, 0x0a, 0x0b, 0x50, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x04, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61,
 	0x73, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x74, 0x61, 0x73, 0x6b,
@@ -528,44 +872,141 @@ var file_api_pecker_v1_peck_proto_rawDesc = []byte{
 	0x65, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08,
 	0x52, 0x10, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63,
 	0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x16, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64,
-	0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x3a, 0x02, 0x38, 0x01, 0x22, 0x8c, 0x02, 0x0a, 0x0c, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63,
-	0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x3b, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e,
-	0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x2e, 0x48, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65,
-	0x72, 0x73, 0x12, 0x35, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x44, 0x79, 0x6e, 0x61, 0x6d,
-	0x69, 0x63, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x1a, 0x3a, 0x0a,
-	0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x38, 0x0a, 0x0a, 0x51, 0x75, 0x65,
-	0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x22, 0x1f, 0x0a, 0x09, 0x50, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
-	0x63, 0x6f, 0x64, 0x65, 0x22, 0x3d, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74,
-	0x61, 0x73, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x74, 0x61, 0x73,
-	0x6b, 0x49, 0x64, 0x22, 0x1f, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79,
-	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
-	0x63, 0x6f, 0x64, 0x65, 0x32, 0x6d, 0x0a, 0x0b, 0x50, 0x65, 0x63, 0x6b, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x70, 0x65, 0x63, 0x6b, 0x12, 0x13, 0x2e, 0x70, 0x65,
-	0x63, 0x6b, 0x65, 0x72, 0x2e, 0x50, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x11, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x50, 0x65, 0x63, 0x6b, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x2e, 0x0a, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x12, 0x13, 0x2e, 0x70, 0x65,
-	0x63, 0x6b, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x11, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x42, 0x12, 0x5a, 0x10, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x65, 0x63, 0x6b, 0x65,
-	0x72, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x09, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x24, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x67, 0x72, 0x70, 0x63, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x25, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x67, 0x72, 0x70, 0x63, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x28, 0x0a, 0x0f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x18,
+	0x26, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x44, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x77, 0x73, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x18, 0x27, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x73, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x12, 0x77, 0x73, 0x43, 0x6f, 0x72, 0x72,
+	0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x28, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x77, 0x73, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x77, 0x73, 0x48, 0x6f, 0x6c, 0x64,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x29, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x77, 0x73, 0x48, 0x6f,
+	0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x2b, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x2c, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x65, 0x72, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x72, 0x12, 0x26,
+	0x0a, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x2d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x6e, 0x73, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x54, 0x79, 0x70, 0x65, 0x18, 0x2e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x6e,
+	0x73, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65,
+	0x64, 0x69, 0x73, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x2f, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x72, 0x65, 0x64, 0x69, 0x73, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
+	0x12, 0x24, 0x0a, 0x0d, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x53, 0x74, 0x65, 0x70,
+	0x73, 0x18, 0x30, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69,
+	0x6f, 0x53, 0x74, 0x65, 0x70, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x68, 0x69, 0x6e, 0x6b, 0x54,
+	0x69, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x31, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x74,
+	0x68, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x68, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x32, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x74, 0x68, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x68,
+	0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x61, 0x78, 0x18, 0x33, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0c, 0x74, 0x68, 0x69, 0x6e, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x61, 0x78, 0x12, 0x1e,
+	0x0a, 0x0a, 0x70, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x34, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x70, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x70, 0x61, 0x63, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x18, 0x35, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x70, 0x61, 0x63, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0d,
+	0x70, 0x61, 0x63, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4e, 0x75, 0x6d, 0x18, 0x36, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x61, 0x63, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4e,
+	0x75, 0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x63, 0x65, 0x72, 0x45, 0x6e, 0x64, 0x4e, 0x75,
+	0x6d, 0x18, 0x37, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x61, 0x63, 0x65, 0x72, 0x45, 0x6e,
+	0x64, 0x4e, 0x75, 0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x63, 0x65, 0x72, 0x50, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x18, 0x38, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x61, 0x63, 0x65, 0x72,
+	0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x63, 0x65, 0x72, 0x53,
+	0x74, 0x65, 0x70, 0x73, 0x18, 0x39, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x61, 0x63, 0x65,
+	0x72, 0x53, 0x74, 0x65, 0x70, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x67, 0x65, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x3a, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0e,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a,
+	0x0a, 0x10, 0x73, 0x74, 0x61, 0x67, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x3b, 0x20, 0x03, 0x28, 0x05, 0x52, 0x10, 0x73, 0x74, 0x61, 0x67, 0x65, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x6d, 0x61,
+	0x78, 0x49, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x6d, 0x61, 0x78, 0x49, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x26, 0x0a, 0x0e,
+	0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x3d,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x32, 0x0a, 0x14, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x51, 0x75, 0x65, 0x75, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x3e, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x14, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x51, 0x75, 0x65, 0x75,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x73, 0x74,
+	0x48, 0x74, 0x74, 0x70, 0x18, 0x3f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x66, 0x61, 0x73, 0x74,
+	0x48, 0x74, 0x74, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x6f, 0x64, 0x79, 0x4d, 0x6f, 0x64, 0x65,
+	0x18, 0x40, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x62, 0x6f, 0x64, 0x79, 0x4d, 0x6f, 0x64, 0x65,
+	0x12, 0x22, 0x0a, 0x0c, 0x62, 0x6f, 0x64, 0x79, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x18, 0x41, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x6f, 0x64, 0x79, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x73, 0x75, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x6c, 0x73, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x18, 0x42, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x6c, 0x73, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6c, 0x73, 0x43, 0x61, 0x18, 0x43, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x6c, 0x73, 0x43, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x6c, 0x73, 0x43,
+	0x65, 0x72, 0x74, 0x18, 0x44, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6c, 0x73, 0x43, 0x65,
+	0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x18, 0x45, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x6c,
+	0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x46, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x74, 0x6c, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x74, 0x6c, 0x73, 0x4d, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x47, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x6c, 0x73, 0x4d, 0x69, 0x6e, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x6c, 0x73, 0x4d, 0x61, 0x78,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x48, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74,
+	0x6c, 0x73, 0x4d, 0x61, 0x78, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0f,
+	0x74, 0x6c, 0x73, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x73, 0x18,
+	0x49, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x6c, 0x73, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x53, 0x75, 0x69, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x6c, 0x73, 0x41, 0x6c, 0x70,
+	0x6e, 0x18, 0x4a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6c, 0x73, 0x41, 0x6c, 0x70, 0x6e,
+	0x12, 0x2c, 0x0a, 0x11, 0x74, 0x6c, 0x73, 0x46, 0x72, 0x65, 0x73, 0x68, 0x48, 0x61, 0x6e, 0x64,
+	0x73, 0x68, 0x61, 0x6b, 0x65, 0x18, 0x4b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x74, 0x6c, 0x73,
+	0x46, 0x72, 0x65, 0x73, 0x68, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x68, 0x6f, 0x73, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x4c, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f, 0x73, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x6e, 0x73, 0x43, 0x61, 0x63, 0x68, 0x65, 0x54, 0x74, 0x6c, 0x18,
+	0x4d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x64, 0x6e, 0x73, 0x43, 0x61, 0x63, 0x68, 0x65, 0x54,
+	0x74, 0x6c, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x4e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x1a, 0x3a, 0x0a, 0x0c,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8c, 0x02, 0x0a, 0x0c, 0x44, 0x79, 0x6e,
+	0x61, 0x6d, 0x69, 0x63, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x3b, 0x0a, 0x07, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x65, 0x63,
+	0x6b, 0x65, 0x72, 0x2e, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x35, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x44,
+	0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x2e, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x12, 0x0a,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64,
+	0x79, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x38, 0x0a,
+	0x0a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1f, 0x0a, 0x09, 0x50, 0x65, 0x63, 0x6b, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x3d, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x74, 0x61, 0x73, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x74, 0x61, 0x73, 0x6b, 0x49, 0x64, 0x22, 0x1f, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x32, 0x6d, 0x0a, 0x0b, 0x50, 0x65, 0x63, 0x6b,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x70, 0x65, 0x63, 0x6b, 0x12,
+	0x13, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x50, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x50, 0x65,
+	0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2e, 0x0a, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x12,
+	0x13, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x53, 0x74,
+	0x6f, 0x70, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x42, 0x12, 0x5a, 0x10, 0x61, 0x70, 0x69, 0x2f, 0x70,
+	0x65, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -10,11 +10,47 @@ CREATE TABLE gopeck.stress_log
     timestamp                     Int64,
     total_num                     Int64,
     total_response_content_length Int64,
-    duration_map Map(Int32, Int64),
+    duration_map Map(Int64, Int64),
     status_map Map(Int32, Int64),
     error_map Map(String, Int64),
     body_check_result_map Map(String, Int64),
-    latency_map Map(String, Int32)
+    latency_map Map(String, Int64),
+    handshake_duration_map Map(Int64, Int64),
+    message_duration_map Map(Int64, Int64),
+    disconnect_num                Int64,
+    first_byte_duration_map Map(Int64, Int64),
+    first_event_duration_map Map(Int64, Int64),
+    event_gap_duration_map Map(Int64, Int64),
+    stream_duration_map Map(Int64, Int64),
+    connect_duration_map Map(Int64, Int64),
+    write_duration_map Map(Int64, Int64),
+    read_duration_map Map(Int64, Int64),
+    dns_duration_map Map(Int64, Int64),
+    tls_duration_map Map(Int64, Int64),
+    wait_duration_map Map(Int64, Int64),
+    transfer_duration_map Map(Int64, Int64),
+    command_duration_map Map(String, Map(Int64, Int64)),
+    step_duration_map Map(String, Map(Int64, Int64)),
+    step_error_map Map(String, Int64),
+    response_duration_map Map(Int64, Int64),
+    late_num                      Int64,
+    skipped_num                   Int64,
+    total_request_bytes           Int64,
+    total_response_bytes          Int64,
+    upload_throughput             Float64,
+    download_throughput           Float64,
+    response_size_map Map(Int64, Int64),
+    tls_handshake_num             Int64,
+    tls_resumed_num               Int64,
+    tls_version_map Map(String, Int64),
+    tls_cipher_map Map(String, Int64),
+    new_conn_num                  Int64,
+    reused_conn_num               Int64,
+    conn_reuse_rate               Float64,
+    peak_open_conn_num            Int64,
+    new_conn_map Map(String, Int64),
+    reused_conn_map Map(String, Int64),
+    peak_open_conn_map Map(String, Int64)
 ) ENGINE = MergeTree
       ORDER BY (timestamp)
       PARTITION BY toYYYYMMDD(toDateTime(timestamp))
@@ -52,13 +52,19 @@ type (
 		// pecker max goroutine num every node, testing number before deployment
 		MaxGoroutineNum int `mapstructure:"max_goroutine_num"`
 		// pecker max rps every node, testing number before deployment
-		MaxRpsNum             int   `mapstructure:"max_rps_num"`
-		MaxResultChanSize     int32 `mapstructure:"max_result_chan_size"`
-		RpsResultChanBlowup   int32 `mapstructure:"rps_result_chan_blowup"`
+		MaxRpsNum         int   `mapstructure:"max_rps_num"`
+		MaxResultChanSize int32 `mapstructure:"max_result_chan_size"`
+		// the max second of the latency histograms, the slower latencies are recorded as it, no limit if zero
 		MaxTimeoutSecond      int64 `mapstructure:"max_timeout_second"`
 		ReportGoroutineNum    int   `mapstructure:"report_goroutine_num"`
 		DefaultMaxConnections int   `mapstructure:"default_max_connections"`
 		ErrorCutLength        int   `mapstructure:"error_cut_length"`
+		// the local source addresses the dialers bind in turn, so that every address has its own ephemeral
+		// ports against one target, the system chooses the source address if empty
+		SourceAddresses []string `mapstructure:"source_addresses"`
+		// the secret shared by the admin and the peckers to encrypt the tls pem material of the tasks, it has no
+		// default, and the tasks with the pem material are rejected if it is not configured
+		TlsSecret string `mapstructure:"tls_secret"`
 	}
 
 	Server struct {
@@ -138,5 +144,6 @@ func ReadConfig(path string) *ServerConf {
 	conf.Data.Redis.Addr = os.ExpandEnv(conf.Data.Redis.Addr)
 	conf.Data.Clickhouse.Addrs = os.ExpandEnv(conf.Data.Clickhouse.Addrs)
 	conf.Data.Etcd.Endpoints = os.ExpandEnv(conf.Data.Etcd.Endpoints)
+	conf.StressConf.TlsSecret = os.ExpandEnv(conf.StressConf.TlsSecret)
 	return &conf
 }
@@ -0,0 +1,302 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/pkg/consts"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/grpcx"
+	"github.com/peckfly/gopeck/pkg/log/logc"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"github.com/peckfly/gopeck/pkg/redisx"
+	"github.com/peckfly/gopeck/pkg/scenario"
+	"github.com/peckfly/gopeck/pkg/tlsx"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpcinsecure "google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	grpcReflectionTimeout = 3 * time.Second
+	socketDialTimeout     = 3 * time.Second
+)
+
+// checkTaskTarget checks the target of the task according to its protocol type, the tls pem material
+// of the task is encrypted with the tls secret.
+func checkTaskTarget(ctx context.Context, task *Task, tlsSecret string) error {
+	switch enums.ProtocolType(task.ProtocolType) {
+	case enums.Grpc:
+		return checkGrpcTarget(ctx, task, tlsSecret)
+	case enums.WebSocket:
+		return checkWebSocketTarget(ctx, task)
+	case enums.Tcp, enums.Udp:
+		return checkSocketTarget(ctx, task)
+	case enums.Dns:
+		return checkDnsTarget(ctx, task)
+	case enums.Redis:
+		return checkRedisTarget(ctx, task)
+	case enums.Scenario:
+		return checkScenarioTarget(ctx, task)
+	default:
+		return checkHttpTarget(ctx, task)
+	}
+}
+
+// checkHttpTarget checks the url and method of the http task, the url should be reachable.
+func checkHttpTarget(ctx context.Context, task *Task) error {
+	if !strings.HasPrefix(task.Url, consts.HttpScheme) && !strings.HasPrefix(task.Url, consts.HttpsScheme) {
+		return fmt.Errorf("URL: %s, should start with http:// or https://", task.Url)
+	}
+	if len(task.Method) == 0 {
+		return fmt.Errorf("URL: %s, method is required", task.Url)
+	}
+	if len(task.Body) > 0 && !json.Valid([]byte(task.Body)) {
+		return fmt.Errorf("URL: %s, body should be json", task.Url)
+	}
+	_, err := url.Parse(task.Url)
+	if err != nil {
+		logc.Info(ctx, "parse url error", zap.String("url", task.Url), zap.Error(err))
+		return fmt.Errorf("URL: %s, parse url error %+v", task.Url, err)
+	}
+	if err = pingTarget(task); err != nil {
+		logc.Error(ctx, "ping url error", zap.String("url", task.Url), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// checkGrpcTarget checks the grpc method against the uploaded descriptor, or the server reflection
+// of the target if no descriptor is uploaded, and the request messages against the method input type.
+func checkGrpcTarget(ctx context.Context, task *Task, tlsSecret string) error {
+	if !strings.HasPrefix(task.Url, grpcx.Scheme) && !strings.HasPrefix(task.Url, grpcx.SecureScheme) {
+		return fmt.Errorf("URL: %s, should start with grpc:// or grpcs://", task.Url)
+	}
+	target, secure := grpcx.ParseTarget(task.Url)
+	var method protoreflect.MethodDescriptor
+	if len(task.ProtoDescriptor) > 0 {
+		files, err := grpcx.DecodeFileDescriptorSet(task.ProtoDescriptor)
+		if err != nil {
+			return err
+		}
+		method, err = grpcx.FindMethod(files, task.GrpcMethod)
+		if err != nil {
+			return err
+		}
+	} else {
+		creds := grpcinsecure.NewCredentials()
+		if secure {
+			config, err := tlsx.NewConfig(taskTlsOptions(task, tlsSecret))
+			if err != nil {
+				return err
+			}
+			creds = credentials.NewTLS(config)
+		}
+		conn, err := grpc.DialContext(ctx, target, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		reflectionCtx, cancel := context.WithTimeout(ctx, grpcReflectionTimeout)
+		defer cancel()
+		method, err = grpcx.ResolveMethodByReflection(reflectionCtx, conn, task.GrpcMethod)
+		if err != nil {
+			logc.Error(ctx, "resolve grpc method by reflection error", zap.String("url", task.Url), zap.Error(err))
+			return fmt.Errorf("URL: %s, resolve method by server reflection error %+v", task.Url, err)
+		}
+	}
+	bodies := []string{task.Body}
+	for _, param := range task.DynamicParams {
+		bodies = append(bodies, param.Body)
+	}
+	for _, body := range bodies {
+		if len(body) == 0 {
+			continue
+		}
+		if err := protojson.Unmarshal([]byte(body), dynamicpb.NewMessage(method.Input())); err != nil {
+			return fmt.Errorf("URL: %s, body is not a valid %s message: %+v", task.Url, method.Input().FullName(), err)
+		}
+	}
+	return nil
+}
+
+// checkWebSocketTarget checks the url of the websocket task should be reachable and the scripted messages.
+func checkWebSocketTarget(ctx context.Context, task *Task) error {
+	if !strings.HasPrefix(task.Url, consts.WsScheme) && !strings.HasPrefix(task.Url, consts.WssScheme) {
+		return fmt.Errorf("URL: %s, should start with ws:// or wss://", task.Url)
+	}
+	if err := pingTarget(task); err != nil {
+		logc.Error(ctx, "ping url error", zap.String("url", task.Url), zap.Error(err))
+		return err
+	}
+	if len(task.WsMessages) > 0 {
+		var messages []json.RawMessage
+		if err := json.Unmarshal([]byte(task.WsMessages), &messages); err != nil {
+			return fmt.Errorf("URL: %s, websocket messages should be a json array", task.Url)
+		}
+	}
+	return nil
+}
+
+// checkSocketTarget checks the address of the tcp or udp task and the payload template, a tcp address
+// should be reachable, while a udp address can only be resolved.
+func checkSocketTarget(ctx context.Context, task *Task) error {
+	network, scheme := "tcp", consts.TcpScheme
+	if enums.ProtocolType(task.ProtocolType) == enums.Udp {
+		network, scheme = "udp", consts.UdpScheme
+	}
+	if !strings.HasPrefix(task.Url, scheme) {
+		return fmt.Errorf("URL: %s, should start with %s", task.Url, scheme)
+	}
+	address := strings.TrimPrefix(task.Url, scheme)
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("URL: %s, address should be host:port", task.Url)
+	}
+	conn, err := net.DialTimeout(network, address, socketDialTimeout)
+	if err != nil {
+		logc.Error(ctx, "dial address error", zap.String("url", task.Url), zap.Error(err))
+		return err
+	}
+	_ = conn.Close()
+	encoding := netx.PayloadEncoding(task.PayloadEncoding)
+	if _, err = netx.BuildPayload(task.Body, encoding, nil); err != nil {
+		return fmt.Errorf("URL: %s, invalid payload: %+v", task.Url, err)
+	}
+	for _, param := range task.DynamicParams {
+		template := task.Body
+		if len(param.Body) > 0 {
+			template = param.Body
+		}
+		if _, err = netx.BuildPayload(template, encoding, param.Query); err != nil {
+			return fmt.Errorf("URL: %s, invalid payload of dynamic params: %+v", task.Url, err)
+		}
+	}
+	if len(task.ResponseDelimiter) > 0 {
+		if _, err = netx.DecodePayload(task.ResponseDelimiter, encoding); err != nil {
+			return fmt.Errorf("URL: %s, invalid response delimiter: %+v", task.Url, err)
+		}
+	}
+	return nil
+}
+
+// checkDnsTarget checks the dns server address and the query names, a server over tcp should be reachable.
+func checkDnsTarget(ctx context.Context, task *Task) error {
+	network, address := "udp", strings.TrimPrefix(task.Url, consts.UdpScheme)
+	if strings.HasPrefix(task.Url, consts.TcpScheme) {
+		network, address = "tcp", strings.TrimPrefix(task.Url, consts.TcpScheme)
+	} else if !strings.HasPrefix(task.Url, consts.UdpScheme) {
+		return fmt.Errorf("URL: %s, dns server should start with udp:// or tcp://", task.Url)
+	}
+	if _, ok := netx.ParseDnsQueryType(task.DnsQueryType); !ok {
+		return fmt.Errorf("URL: %s, not support dns query type: %s", task.Url, task.DnsQueryType)
+	}
+	if network == "tcp" {
+		conn, err := net.DialTimeout(network, netx.DnsServerAddress(address), socketDialTimeout)
+		if err != nil {
+			logc.Error(ctx, "dial dns server error", zap.String("url", task.Url), zap.Error(err))
+			return err
+		}
+		_ = conn.Close()
+	}
+	names := []string{task.Body}
+	if len(task.DynamicParams) > 0 {
+		names = names[:0]
+		for _, param := range task.DynamicParams {
+			names = append(names, param.Body)
+		}
+	}
+	for _, name := range names {
+		if len(strings.TrimSpace(name)) == 0 {
+			return fmt.Errorf("URL: %s, dns query name is required", task.Url)
+		}
+		if _, err := netx.ParseDnsName(name); err != nil {
+			return fmt.Errorf("URL: %s, invalid dns query name %s: %+v", task.Url, name, err)
+		}
+	}
+	return nil
+}
+
+// checkRedisTarget checks the redis url should be reachable and the commands.
+func checkRedisTarget(ctx context.Context, task *Task) error {
+	if !strings.HasPrefix(task.Url, consts.RedisScheme) && !strings.HasPrefix(task.Url, consts.RedissScheme) {
+		return fmt.Errorf("URL: %s, should start with redis:// or rediss://", task.Url)
+	}
+	options, err := redis.ParseURL(task.Url)
+	if err != nil {
+		return fmt.Errorf("URL: %s, parse redis url error %+v", task.Url, err)
+	}
+	conn, err := net.DialTimeout("tcp", options.Addr, socketDialTimeout)
+	if err != nil {
+		logc.Error(ctx, "dial redis error", zap.String("url", task.Url), zap.Error(err))
+		return err
+	}
+	_ = conn.Close()
+	if _, err = redisx.ParseCommands(task.RedisCommands); err != nil {
+		return fmt.Errorf("URL: %s, invalid redis commands: %+v", task.Url, err)
+	}
+	return nil
+}
+
+// checkScenarioTarget checks the base url should be reachable and the scenario steps.
+func checkScenarioTarget(ctx context.Context, task *Task) error {
+	if !strings.HasPrefix(task.Url, consts.HttpScheme) && !strings.HasPrefix(task.Url, consts.HttpsScheme) {
+		return fmt.Errorf("URL: %s, should start with http:// or https://", task.Url)
+	}
+	if err := pingTarget(task); err != nil {
+		logc.Error(ctx, "ping url error", zap.String("url", task.Url), zap.Error(err))
+		return err
+	}
+	if _, err := scenario.ParseSteps(task.ScenarioSteps, task.Url); err != nil {
+		return fmt.Errorf("URL: %s, invalid scenario steps: %+v", task.Url, err)
+	}
+	return nil
+}
+
+// checkTaskTls encrypts the pem material of the task with the tls secret to be stored and delivered, and checks
+// the tls settings, the material encrypted already, e.g. of a restarted task, is kept as it is.
+func checkTaskTls(task *Task, tlsSecret string) error {
+	for _, pem := range []*string{&task.TlsCa, &task.TlsCert, &task.TlsKey} {
+		encrypted, err := tlsx.EncryptPem(*pem, tlsSecret)
+		if err != nil {
+			return fmt.Errorf("URL: %s, %w", task.Url, err)
+		}
+		*pem = encrypted
+	}
+	if _, err := tlsx.NewConfig(taskTlsOptions(task, tlsSecret)); err != nil {
+		return fmt.Errorf("URL: %s, invalid tls settings %+v", task.Url, err)
+	}
+	return nil
+}
+
+func taskTlsOptions(task *Task, tlsSecret string) *tlsx.Options {
+	return &tlsx.Options{
+		Secret:       tlsSecret,
+		Verify:       task.TlsVerify || parseOtherOptions(task.Options, tlsVerify),
+		Ca:           task.TlsCa,
+		Cert:         task.TlsCert,
+		Key:          task.TlsKey,
+		ServerName:   task.TlsServerName,
+		MinVersion:   task.TlsMinVersion,
+		MaxVersion:   task.TlsMaxVersion,
+		CipherSuites: task.TlsCipherSuites,
+		Alpn:         task.TlsAlpn,
+	}
+}
+
+// pingTarget checks the url of the task is reachable, the host is dialed by the host mapping of the task
+func pingTarget(task *Task) error {
+	mapping, err := netx.ParseHostMapping(task.HostMapping)
+	if err != nil {
+		return fmt.Errorf("URL: %s, %w", task.Url, err)
+	}
+	return netx.PingMapped(task.Url, mapping)
+}
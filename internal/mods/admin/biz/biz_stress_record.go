@@ -23,6 +23,10 @@ func (s *StressUsecase) insertPlanTaskRecord(ctx context.Context, in *Plan) erro
 	if in.StartTime <= 0 {
 		stressStartTime = current
 	}
+	stages, err := json.Marshal(in.Stages)
+	if err != nil {
+		return err
+	}
 	pr := &repo.PlanRecord{
 		PlanId:           in.PlanId,
 		UserId:           in.UserId,
@@ -34,10 +38,15 @@ func (s *StressUsecase) insertPlanTaskRecord(ctx context.Context, in *Plan) erro
 		StartTime:        stressStartTime,
 		StepIntervalTime: in.StepIntervalTime,
 		IntervalLen:      in.IntervalLen,
+		PacerType:        in.PacerType,
+		PacerPeriod:      in.PacerPeriod,
+		PacerSteps:       in.PacerSteps,
+		PacerAmplitude:   in.PacerAmplitude,
+		Stages:           string(stages),
 		CreateTime:       current,
 		UpdateTime:       current,
 	}
-	err := s.recordRepository.CreatePlan(ctx, pr)
+	err = s.recordRepository.CreatePlan(ctx, pr)
 	if err != nil {
 		return err
 	}
@@ -75,6 +84,9 @@ func (s *StressUsecase) insertPlanTaskRecord(ctx context.Context, in *Plan) erro
 			}
 		}
 		taskRecord.H2 = cast.ToInt8(task.H2)
+		taskRecord.FastHttp = cast.ToInt8(task.FastHttp)
+		taskRecord.TlsVerify = cast.ToInt8(task.TlsVerify)
+		taskRecord.TlsFreshHandshake = cast.ToInt8(task.TlsFreshHandshake)
 		var nodeAddrs []string
 		for _, node := range task.nodes {
 			nodeAddrs = append(nodeAddrs, node.nodeInfo.Addr)
@@ -137,6 +149,7 @@ func (s *StressUsecase) QueryPlanTaskRecords(ctx context.Context, userId string,
 	if err != nil {
 		return nil, err
 	}
+	stages := getFromStageList(planRecord.Stages)
 	for i := range records {
 		items[i] = new(TaskResultItem)
 		copier.Copy(items[i], records[i])
@@ -160,6 +173,10 @@ func (s *StressUsecase) QueryPlanTaskRecords(ctx context.Context, userId string,
 			startNum := items[i].Num
 			for j := range items[i].Reports {
 				items[i].Reports[j].Num = startNum
+				if j < len(stages) {
+					// every report is a stage in stage mode
+					items[i].Reports[j].Num = stages[j].Target
+				}
 				startNum += items[i].StepNum
 				items[i].Reports[j].Lat90 = findLatencyPercent(items[i].Reports[j].LatencyDistribution, "90.0%")
 				items[i].Reports[j].Lat95 = findLatencyPercent(items[i].Reports[j].LatencyDistribution, "95.0%")
@@ -191,6 +208,7 @@ func (s *StressUsecase) QueryPlanAndTaskRecords(ctx context.Context, userId stri
 	copier.Copy(planResult, planRecord)
 	planResult.StartTime = 0 //reset startTime
 	planResult.StressTime /= int(time.Minute / time.Second)
+	planResult.Stages = getFromStageList(planRecord.Stages)
 	tasks := make([]Task, len(records))
 	for i := range records {
 		copier.Copy(&tasks[i], records[i])
@@ -211,6 +229,15 @@ func getFromEntryList(entryStr string) []Entry {
 	return entryList
 }
 
+func getFromStageList(stageStr string) []Stage {
+	var stages []Stage
+	err := json.Unmarshal([]byte(stageStr), &stages)
+	if err != nil {
+		return nil
+	}
+	return stages
+}
+
 func getTaskOption(record *repo.TaskRecord) []string {
 	var options []string
 	if record.DisableKeepAlive == 1 {
@@ -225,6 +252,15 @@ func getTaskOption(record *repo.TaskRecord) []string {
 	if record.H2 == 1 {
 		options = append(options, h2)
 	}
+	if record.FastHttp == 1 {
+		options = append(options, fastHttp)
+	}
+	if record.TlsVerify == 1 {
+		options = append(options, tlsVerify)
+	}
+	if record.TlsFreshHandshake == 1 {
+		options = append(options, tlsFreshHandshake)
+	}
 	return options
 }
 
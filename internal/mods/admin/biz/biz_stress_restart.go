@@ -37,13 +37,29 @@ func (s *StressUsecase) RestartStress(ctx context.Context, restart *Restart) err
 		task.DisableKeepAlive = taskRecord.DisableKeepAlive == 1
 		task.DisableCompression = taskRecord.DisableCompression == 1
 		task.H2 = taskRecord.H2 == 1
+		task.FastHttp = taskRecord.FastHttp == 1
+		task.TlsVerify = taskRecord.TlsVerify == 1
+		task.TlsFreshHandshake = taskRecord.TlsFreshHandshake == 1
 		tasks = append(tasks, task)
 	}
+	var stages []Stage
+	if len(planRecord.Stages) > 0 {
+		err = json.Unmarshal([]byte(planRecord.Stages), &stages)
+		if err != nil {
+			return err
+		}
+	}
 	// todo startTime design
 	return s.StartStress(ctx, &Plan{
 		StressTime: planRecord.StressTime,
 		UserId:     restart.UserId,
 		PlanName:   planRecord.PlanName,
 		Tasks:      tasks,
+
+		PacerType:      planRecord.PacerType,
+		PacerPeriod:    planRecord.PacerPeriod,
+		PacerSteps:     planRecord.PacerSteps,
+		PacerAmplitude: planRecord.PacerAmplitude,
+		Stages:         stages,
 	})
 }
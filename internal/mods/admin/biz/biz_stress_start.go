@@ -2,6 +2,8 @@ package biz
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/jinzhu/copier"
@@ -11,6 +13,7 @@ import (
 	"github.com/peckfly/gopeck/internal/mods/common/repo"
 	"github.com/peckfly/gopeck/internal/pkg/consts"
 	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/internal/pkg/errors"
 	"github.com/peckfly/gopeck/pkg/interpreter"
 	"github.com/peckfly/gopeck/pkg/log/logc"
 	"github.com/peckfly/gopeck/pkg/netx"
@@ -21,7 +24,6 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	grpcinsecure "google.golang.org/grpc/credentials/insecure"
-	"net/url"
 	"strings"
 	"time"
 )
@@ -71,10 +73,14 @@ func (s *StressUsecase) StartStress(ctx context.Context, in *Plan) error {
 		return err
 	}
 	in.PlanId = planId
-	in.StressTime *= int(time.Minute / time.Second)
+	stressModeType := enums.StressModeType(in.StressMode)
+	if stressModeType == enums.Stage {
+		in.StressTime = stagesDuration(in.Stages)
+	} else {
+		in.StressTime *= int(time.Minute / time.Second)
+	}
 	in.StepIntervalTime *= int(time.Minute / time.Second)
 	var updateNodes [][2]*repo.Node
-	stressModeType := enums.StressModeType(in.StressMode)
 	isStepMode := stressModeType == enums.Step
 	intervalLen := 1 // adaptive with default mode
 	if isStepMode {
@@ -83,6 +89,10 @@ func (s *StressUsecase) StartStress(ctx context.Context, in *Plan) error {
 	} else {
 		// adaptive with default mode
 		in.StepIntervalTime = in.StressTime
+		if stressModeType == enums.Stage {
+			// every stage is an interval of the reports
+			intervalLen = len(in.Stages)
+		}
 	}
 	in.IntervalLen = intervalLen
 	err = s.assignAndCalculateTask(planId, in, isStepMode, intervalLen, nodeCostInfos, updateNodes)
@@ -99,7 +109,7 @@ func (s *StressUsecase) StartStress(ctx context.Context, in *Plan) error {
 				logc.Error(ctx, "failed to dial node", zap.String("addr", node.nodeInfo.Addr), zap.Error(err))
 				return err
 			}
-			connMap[task.TaskId] = append(connMap[task.TaskId], &BindConn{node.num, node.Nums, node.nodeInfo.Addr, conn})
+			connMap[task.TaskId] = append(connMap[task.TaskId], &BindConn{node.num, node.Nums, node.nodeInfo.Addr, conn, node.pacerStartNum, node.pacerEndNum})
 		}
 	}
 	err = s.insertPlanTaskRecord(ctx, in)
@@ -133,14 +143,24 @@ func (s *StressUsecase) assignAndCalculateTask(planId uint64, in *Plan, isStepMo
 		}
 		s.taskParamSetting(in, i, taskId, planId)
 		planStressType := enums.StressType(in.Tasks[i].StressType)
-		curNum := task.Num
+		curNum := in.Tasks[i].Num
 		if isStepMode {
 			curNum = max(task.Num, task.MaxNum)
 		}
+		// the pacer rate varies from the start num to the end num, the node costs are split by the peak
+		pacerStartNum, pacerEndNum := pacerRange(in.Tasks[i])
+		if planStressType == enums.Rps && !isStepMode {
+			curNum = max(pacerStartNum, pacerEndNum)
+		}
 		totNums := curNum
+		pacerStartCost, pacerEndCost := 0, 0
+		targets := intervalTargets(in, in.Tasks[i], intervalLen)
 		costs := make([]int32, intervalLen)
 		assigned := false
 		for _, node := range nodeCostInfos {
+			if in.Tasks[i].FastHttp && !supportHttpEngine(node.instance, consts.FastHttpEngine) {
+				continue
+			}
 			nodeMaxConcurrencyNum, nodeMaxRpsNum := node.GoroutineQuota, node.RpsQuota
 			var leftNum int
 			if node.RpsCost > 0 {
@@ -162,19 +182,7 @@ func (s *StressUsecase) assignAndCalculateTask(planId uint64, in *Plan, isStepMo
 			}
 			addCostNum := min(curNum, leftNum)
 			curNum -= addCostNum
-			nums := make([]int32, intervalLen)
-			if isStepMode {
-				startNum := task.Num
-				for j := 0; j < intervalLen; j++ {
-					nums[j] = int32(startNum * addCostNum / totNums)
-					if curNum <= 0 {
-						nums[j] = int32(startNum) - costs[j]
-					} else {
-						costs[j] += nums[j]
-					}
-					startNum = min(startNum+task.StepNum, task.MaxNum)
-				}
-			}
+			nums := nodeTargets(targets, costs, addCostNum, totNums, curNum <= 0)
 			// updateStatus
 			newNodeInfo := &repo.Node{
 				Addr:             node.instance.Addr,
@@ -205,8 +213,15 @@ func (s *StressUsecase) assignAndCalculateTask(planId uint64, in *Plan, isStepMo
 					newNodeInfo,
 				})
 			}
+			nodePacerStartNum, nodePacerEndNum := pacerStartNum*addCostNum/totNums, pacerEndNum*addCostNum/totNums
+			if curNum <= 0 {
+				// the last node takes the rest to keep the total rate
+				nodePacerStartNum, nodePacerEndNum = pacerStartNum-pacerStartCost, pacerEndNum-pacerEndCost
+			}
+			pacerStartCost += nodePacerStartNum
+			pacerEndCost += nodePacerEndNum
 			// send request to this address
-			in.Tasks[i].nodes = append(in.Tasks[i].nodes, &BindNode{addCostNum, nums, newNodeInfo})
+			in.Tasks[i].nodes = append(in.Tasks[i].nodes, &BindNode{addCostNum, nums, newNodeInfo, nodePacerStartNum, nodePacerEndNum})
 			// check left curNum
 			if curNum <= 0 {
 				assigned = true
@@ -220,6 +235,66 @@ func (s *StressUsecase) assignAndCalculateTask(planId uint64, in *Plan, isStepMo
 	return nil
 }
 
+// intervalTargets returns the rps or concurrency of the task in every interval, it grows from the num
+// by the step num up to the max num in step mode, and it is the target of every stage in stage mode
+func intervalTargets(in *Plan, task Task, intervalLen int) []int {
+	switch enums.StressModeType(in.StressMode) {
+	case enums.Step:
+		targets := make([]int, intervalLen)
+		num := task.Num
+		for j := range targets {
+			targets[j] = num
+			num = min(num+task.StepNum, task.MaxNum)
+		}
+		return targets
+	case enums.Stage:
+		targets := make([]int, len(in.Stages))
+		for j, stage := range in.Stages {
+			targets[j] = stage.Target
+		}
+		return targets
+	default:
+		return nil
+	}
+}
+
+// nodeTargets returns the targets of the node which takes addCostNum of the totNums, the targets assigned to
+// the former nodes are added up in costs, and the last node takes the rest to keep the targets of the task
+func nodeTargets(targets []int, costs []int32, addCostNum, totNums int, last bool) []int32 {
+	nums := make([]int32, len(costs))
+	for j, target := range targets {
+		nums[j] = int32(target * addCostNum / totNums)
+		if last {
+			nums[j] = int32(target) - costs[j]
+		} else {
+			costs[j] += nums[j]
+		}
+	}
+	return nums
+}
+
+// stagesDuration returns the sum of the stage durations in seconds
+func stagesDuration(stages []Stage) int {
+	total := 0
+	for _, stage := range stages {
+		total += stage.Duration
+	}
+	return total
+}
+
+// pacerRange returns the start and end rate of the task pacer, they are the mean and the peak rate
+// for the sine pacer, and both the num for the constant and poisson pacer
+func pacerRange(task Task) (int, int) {
+	switch enums.PacerType(task.PacerType) {
+	case enums.LinearPacer, enums.SawtoothPacer:
+		return task.Num, task.MaxNum
+	case enums.SinePacer:
+		return task.Num, task.Num * (100 + task.PacerAmplitude) / 100
+	default:
+		return task.Num, task.Num
+	}
+}
+
 // taskParamSetting sets the parameters of a task in a StressUsecase.
 //
 // Parameters:
@@ -234,6 +309,19 @@ func (s *StressUsecase) taskParamSetting(in *Plan, i int, taskId uint64, planId
 	in.Tasks[i].StressType = in.StressType
 	in.Tasks[i].StressMode = in.StressMode
 	in.Tasks[i].StepIntervalTime = in.StepIntervalTime
+	in.Tasks[i].PacerType = in.PacerType
+	in.Tasks[i].PacerPeriod = in.PacerPeriod
+	in.Tasks[i].PacerSteps = in.PacerSteps
+	in.Tasks[i].PacerAmplitude = in.PacerAmplitude
+	if enums.StressModeType(in.StressMode) == enums.Stage {
+		// the nodes are assigned by the peak target of the stages
+		in.Tasks[i].Num = 0
+		for _, stage := range in.Stages {
+			in.Tasks[i].StageDurations = append(in.Tasks[i].StageDurations, int32(stage.Duration))
+			in.Tasks[i].StageTransitions = append(in.Tasks[i].StageTransitions, int32(stage.Transition))
+			in.Tasks[i].Num = max(in.Tasks[i].Num, stage.Target)
+		}
+	}
 	in.Tasks[i].Headers = parseEntryList(in.Tasks[i].HeaderEntry)
 	in.Tasks[i].Query = parseEntryList(in.Tasks[i].QueryEntry)
 	if in.Tasks[i].MaxConnections <= 0 {
@@ -247,6 +335,9 @@ func (s *StressUsecase) taskParamSetting(in *Plan, i int, taskId uint64, planId
 	in.Tasks[i].DisableKeepAlive = parseOtherOptions(in.Tasks[i].Options, disableKeepAlive)
 	in.Tasks[i].DisableRedirects = parseOtherOptions(in.Tasks[i].Options, disableRedirects)
 	in.Tasks[i].H2 = parseOtherOptions(in.Tasks[i].Options, h2)
+	in.Tasks[i].FastHttp = parseOtherOptions(in.Tasks[i].Options, fastHttp)
+	in.Tasks[i].TlsVerify = parseOtherOptions(in.Tasks[i].Options, tlsVerify)
+	in.Tasks[i].TlsFreshHandshake = parseOtherOptions(in.Tasks[i].Options, tlsFreshHandshake)
 }
 
 // getNodeCostInfoByInstances generates NodeInstanceCost based on services and Node info.
@@ -299,28 +390,54 @@ func (s *StressUsecase) preCheck(ctx context.Context, in *Plan) error {
 	if len(in.Tasks) > maxTaskCount {
 		return fmt.Errorf("task count should be less than %d", maxTaskCount)
 	}
+	if err := checkPacer(in); err != nil {
+		return err
+	}
+	if err := checkStages(in); err != nil {
+		return err
+	}
 	for i, task := range in.Tasks {
-		if !strings.HasPrefix(task.Url, consts.HttpScheme) && !strings.HasPrefix(task.Url, consts.HttpsScheme) {
-			return fmt.Errorf("URL: %s, should start with http:// or https://", task.Url)
-		}
-		_, err := url.Parse(task.Url)
-		if err != nil {
-			logc.Info(ctx, "parse url error", zap.String("url", task.Url), zap.Error(err))
-			return fmt.Errorf("URL: %s, parse url error %+v", task.Url, err)
+		if task.ProtocolType <= 0 {
+			// tasks created before protocol type was introduced are http tasks
+			task.ProtocolType = int(enums.Http)
+			in.Tasks[i].ProtocolType = task.ProtocolType
 		}
-		if err = netx.Ping(task.Url); err != nil {
-			logc.Error(ctx, "ping url error", zap.String("url", task.Url), zap.Error(err))
-			return err
-		}
-		if len(task.Body) > 0 && !json.Valid([]byte(task.Body)) {
-			return fmt.Errorf("URL: %s, body should be json", task.Url)
+		if !enums.SupportProtocolType(task.ProtocolType) {
+			return fmt.Errorf("not support protocol type: %d", task.ProtocolType)
 		}
+		var err error
 		if len(task.DynamicParamScript) > 0 {
 			in.Tasks[i].DynamicParams, err = parseDynamicParams(task)
 			if err != nil {
 				return err
 			}
 		}
+		if _, err = netx.ParseHostMapping(task.HostMapping); err != nil {
+			return fmt.Errorf("URL: %s, %w", task.Url, err)
+		}
+		if _, err = netx.ParseIPs(task.SourceAddresses); err != nil {
+			return fmt.Errorf("URL: %s, source addresses: %w", task.Url, err)
+		}
+		if err = checkTaskTls(&in.Tasks[i], s.stressConf.TlsSecret); err != nil {
+			return err
+		}
+		if err = checkTaskTarget(ctx, &in.Tasks[i], s.stressConf.TlsSecret); err != nil {
+			return err
+		}
+		if enums.ThinkTimeType(task.ThinkTimeType) == enums.UniformThinkTime && task.ThinkTimeMax < task.ThinkTime {
+			return fmt.Errorf("URL: %s, max think time should not be less than think time", task.Url)
+		}
+		if enums.InFlightPolicy(task.InFlightPolicy) == enums.QueueInFlight && task.InFlightQueueTimeout <= 0 {
+			return fmt.Errorf("URL: %s, queue timeout is required by the queue in-flight policy", task.Url)
+		}
+		if parseOtherOptions(task.Options, fastHttp) && parseOtherOptions(task.Options, h2) {
+			return fmt.Errorf("URL: %s, fasthttp engine does not support http2", task.Url)
+		}
+		if enums.BodyMode(task.BodyMode) == enums.HashBody {
+			if checksum, err := hex.DecodeString(task.BodyChecksum); err != nil || len(checksum) != sha256.Size {
+				return fmt.Errorf("URL: %s, body checksum should be a sha256 checksum in hex", task.Url)
+			}
+		}
 		if len(task.ResponseCheckScript) > 0 {
 			err = checkResponseCheckScript(task.ResponseCheckScript)
 			if err != nil {
@@ -331,6 +448,72 @@ func (s *StressUsecase) preCheck(ctx context.Context, in *Plan) error {
 	return nil
 }
 
+// checkPacer checks the pacer of the plan, only the rps constant mode can choose a pacer.
+func checkPacer(in *Plan) error {
+	pacerType := enums.PacerType(in.PacerType)
+	if pacerType <= enums.ConstantPacer {
+		return nil
+	}
+	if enums.StressType(in.StressType) != enums.Rps || enums.StressModeType(in.StressMode) != enums.Constants {
+		return fmt.Errorf("pacer is only supported by rps constant mode")
+	}
+	switch pacerType {
+	case enums.LinearPacer, enums.SawtoothPacer:
+		for _, task := range in.Tasks {
+			if task.MaxNum <= 0 {
+				return fmt.Errorf("URL: %s, max num is required by the pacer", task.Url)
+			}
+		}
+		if pacerType == enums.SawtoothPacer && in.PacerPeriod <= 0 {
+			return fmt.Errorf("pacer period is required by the sawtooth pacer")
+		}
+	case enums.SinePacer:
+		if in.PacerPeriod <= 0 {
+			return fmt.Errorf("pacer period is required by the sine pacer")
+		}
+		if in.PacerAmplitude < 0 || in.PacerAmplitude > 100 {
+			return errors.BadRequest("", "pacer amplitude should be between 0 and 100, got %d", in.PacerAmplitude)
+		}
+	}
+	return nil
+}
+
+// checkStages checks the stages of stage mode, the other modes run for the stress time.
+func checkStages(in *Plan) error {
+	if enums.StressModeType(in.StressMode) != enums.Stage {
+		if len(in.Stages) > 0 {
+			return fmt.Errorf("stages are only supported by stage mode")
+		}
+		if in.StressTime <= 0 {
+			return fmt.Errorf("stress time should be greater than 0")
+		}
+		return nil
+	}
+	if len(in.Stages) == 0 {
+		return fmt.Errorf("stages are required by stage mode")
+	}
+	if len(in.Stages) > maxStageCount {
+		return fmt.Errorf("stage count should be less than %d", maxStageCount)
+	}
+	peak := 0
+	for i, stage := range in.Stages {
+		if stage.Duration <= 0 {
+			return fmt.Errorf("stage %d: duration should be greater than 0", i+1)
+		}
+		if stage.Target < 0 {
+			return fmt.Errorf("stage %d: target should not be negative", i+1)
+		}
+		if stage.Transition < 0 || enums.StageTransition(stage.Transition) > enums.InstantTransition {
+			return fmt.Errorf("stage %d: not support transition: %d", i+1, stage.Transition)
+		}
+		peak = max(peak, stage.Target)
+	}
+	if peak <= 0 {
+		return fmt.Errorf("the target of at least one stage should be greater than 0")
+	}
+	return nil
+}
+
 // DeleteNodeStateInfo deletes node state information.
 //
 // ctx: The context in which the function is being called.
@@ -372,6 +555,8 @@ func (s *StressUsecase) sendRequest(ctx context.Context, conns []*BindConn, task
 		request.Num = int32(conn.num)
 		request.Nums = conn.Nums
 		request.Addr = conn.Addr
+		request.PacerStartNum = int32(conn.pacerStartNum)
+		request.PacerEndNum = int32(conn.pacerEndNum)
 		for retryTimes := 0; retryTimes < 3; retryTimes++ {
 			peckReply, err := peckServiceClient.Peck(ctx, &request)
 			if err != nil {
@@ -404,6 +589,10 @@ func (s *StressUsecase) receiveResults(ctx context.Context, in *Plan) error {
 		}
 	}()
 	integrateServiceClient := integratorv1.NewIntegrateServiceClient(grpcClientConn)
+	var stageDurations []int32
+	for _, stage := range in.Stages {
+		stageDurations = append(stageDurations, int32(stage.Duration))
+	}
 	var tasks []*integratorv1.Task
 	for _, task := range in.Tasks {
 		tasks = append(tasks, &integratorv1.Task{
@@ -422,6 +611,7 @@ func (s *StressUsecase) receiveResults(ctx context.Context, in *Plan) error {
 		StepIntervalTime: int32(in.StepIntervalTime),
 		UserId:           in.UserId,
 		Tasks:            tasks,
+		StageDurations:   stageDurations,
 	})
 	logc.Info(ctx, "integrate request success", zap.Any("integrateReply", integrateReply))
 	return err
@@ -517,6 +707,16 @@ func getTaskByTaskId(id uint64, tasks []Task) (t Task) {
 	return
 }
 
+// supportHttpEngine checks if the http engine is advertised in the metadata of the node
+func supportHttpEngine(instance *registry.ServiceInstance, engine string) bool {
+	for _, e := range strings.Split(instance.Metadata[consts.HttpEngines], ",") {
+		if e == engine {
+			return true
+		}
+	}
+	return false
+}
+
 // parseOtherOptions checks if the given compression option exists in the options slice.
 //
 // Parameters:
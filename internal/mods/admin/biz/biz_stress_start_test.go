@@ -0,0 +1,154 @@
+package biz
+
+import (
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIntervalTargets(t *testing.T) {
+	tests := []struct {
+		name        string
+		plan        *Plan
+		task        Task
+		intervalLen int
+		want        []int
+	}{
+		{"constants", &Plan{StressMode: int(enums.Constants)}, Task{Num: 10}, 1, nil},
+		{"step", &Plan{StressMode: int(enums.Step)}, Task{Num: 10, StepNum: 5, MaxNum: 22}, 5, []int{10, 15, 20, 22, 22}},
+		{"step without growth", &Plan{StressMode: int(enums.Step)}, Task{Num: 10, MaxNum: 10}, 2, []int{10, 10}},
+		{"stage", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: 10, Target: 100}, {Duration: 20, Target: 0}, {Duration: 5, Target: 30}}}, Task{Num: 100}, 3, []int{100, 0, 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, intervalTargets(tt.plan, tt.task, tt.intervalLen))
+		})
+	}
+}
+
+func TestNodeTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []int
+		shares  []int
+		want    [][]int32
+	}{
+		{"single node", []int{10, 20}, []int{30}, [][]int32{{10, 20}}},
+		{"even split", []int{10, 20}, []int{15, 15}, [][]int32{{5, 10}, {5, 10}}},
+		{"last node takes the rest", []int{10, 35, 1}, []int{3, 3, 3}, [][]int32{{3, 11, 0}, {3, 11, 0}, {4, 13, 1}}},
+		{"uneven quotas", []int{100, 0, 30}, []int{70, 30}, [][]int32{{70, 0, 21}, {30, 0, 9}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totNums := 0
+			for _, share := range tt.shares {
+				totNums += share
+			}
+			costs := make([]int32, len(tt.targets))
+			sums := make([]int32, len(tt.targets))
+			curNum := totNums
+			for i, share := range tt.shares {
+				curNum -= share
+				nums := nodeTargets(tt.targets, costs, share, totNums, curNum <= 0)
+				assert.Equal(t, tt.want[i], nums, "node %d", i)
+				for j, num := range nums {
+					sums[j] += num
+				}
+			}
+			for j, target := range tt.targets {
+				assert.Equal(t, int32(target), sums[j], "interval %d", j)
+			}
+		})
+	}
+}
+
+func TestStagesDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		stages []Stage
+		want   int
+	}{
+		{"no stage", nil, 0},
+		{"one stage", []Stage{{Duration: 30, Target: 10}}, 30},
+		{"stages", []Stage{{Duration: 30, Target: 10}, {Duration: 60, Target: 50}, {Duration: 15}}, 105},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stagesDuration(tt.stages))
+		})
+	}
+}
+
+func TestCheckStages(t *testing.T) {
+	tooMany := make([]Stage, maxStageCount+1)
+	for i := range tooMany {
+		tooMany[i] = Stage{Duration: 1, Target: 1}
+	}
+	tests := []struct {
+		name    string
+		plan    *Plan
+		wantErr bool
+	}{
+		{"stress time", &Plan{StressMode: int(enums.Constants), StressTime: 1}, false},
+		{"no stress time", &Plan{StressMode: int(enums.Constants)}, true},
+		{"stages without stage mode", &Plan{StressMode: int(enums.Step), StressTime: 1, Stages: []Stage{{Duration: 1, Target: 1}}}, true},
+		{"stages", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: 10, Target: 100}, {Duration: 10, Target: 0, Transition: int(enums.InstantTransition)}}}, false},
+		{"max stages", &Plan{StressMode: int(enums.Stage), Stages: tooMany[:maxStageCount]}, false},
+		{"empty stages", &Plan{StressMode: int(enums.Stage)}, true},
+		{"oversized stages", &Plan{StressMode: int(enums.Stage), Stages: tooMany}, true},
+		{"zero length stage", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: 10, Target: 100}, {Duration: 0, Target: 50}}}, true},
+		{"negative length stage", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: -1, Target: 100}}}, true},
+		{"negative target", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: 10, Target: -1}}}, true},
+		{"unknown transition", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: 10, Target: 1, Transition: 3}}}, true},
+		{"no positive target", &Plan{StressMode: int(enums.Stage), Stages: []Stage{{Duration: 10}, {Duration: 10}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStages(tt.plan)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckPacer(t *testing.T) {
+	sine := func(amplitude int) *Plan {
+		return &Plan{
+			StressType:     int(enums.Rps),
+			StressMode:     int(enums.Constants),
+			PacerType:      int(enums.SinePacer),
+			PacerPeriod:    10,
+			PacerAmplitude: amplitude,
+		}
+	}
+	tests := []struct {
+		name    string
+		plan    *Plan
+		wantErr bool
+	}{
+		{"constant pacer", &Plan{StressType: int(enums.Rps), StressMode: int(enums.Constants)}, false},
+		{"pacer without rps constant mode", &Plan{StressType: int(enums.Rps), StressMode: int(enums.Step), PacerType: int(enums.SinePacer), PacerPeriod: 10}, true},
+		{"linear without max num", &Plan{StressType: int(enums.Rps), StressMode: int(enums.Constants), PacerType: int(enums.LinearPacer), Tasks: []Task{{Num: 10}}}, true},
+		{"linear", &Plan{StressType: int(enums.Rps), StressMode: int(enums.Constants), PacerType: int(enums.LinearPacer), Tasks: []Task{{Num: 10, MaxNum: 100}}}, false},
+		{"sawtooth without period", &Plan{StressType: int(enums.Rps), StressMode: int(enums.Constants), PacerType: int(enums.SawtoothPacer), Tasks: []Task{{Num: 10, MaxNum: 100}}}, true},
+		{"sine without period", &Plan{StressType: int(enums.Rps), StressMode: int(enums.Constants), PacerType: int(enums.SinePacer)}, true},
+		{"sine", sine(50), false},
+		{"zero amplitude", sine(0), false},
+		{"full amplitude", sine(100), false},
+		{"negative amplitude", sine(-1), true},
+		{"oversized amplitude", sine(101), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPacer(tt.plan)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -20,11 +20,15 @@ const (
 	disableKeepAlive   = "disableKeepAlive"
 	disableRedirects   = "disableRedirect"
 	h2                 = "enableHttp2"
+	fastHttp           = "fastHttp"
+	tlsVerify          = "tlsVerify"
+	tlsFreshHandshake  = "tlsFreshHandshake"
 )
 
 const (
 	maxTimeoutSecond = 5
 	maxTaskCount     = 50
+	maxStageCount    = 100
 )
 
 const (
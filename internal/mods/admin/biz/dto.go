@@ -14,13 +14,30 @@ type (
 		PlanId           uint64 `json:"-"`
 		PlanName         string `json:"plan_name"`
 		StartTime        int64  `json:"start_time"` // todo if StartTime > 0 then use scheduleTask
-		StressTime       int    `json:"stress_time" binding:"required,min=1"`
+		StressTime       int    `json:"stress_time" binding:"min=0"`
 		StressType       int    `json:"stress_type" binding:"required,min=1,max=2"`
-		StressMode       int    `json:"stress_mode" binding:"required,min=1,max=2"`
+		StressMode       int    `json:"stress_mode" binding:"required,min=1,max=3"`
 		UserId           string `json:"user_id"`
 		Tasks            []Task `json:"tasks"`
 		StepIntervalTime int    `json:"step_interval_time"`
 		IntervalLen      int    `json:"-"`
+
+		// pacer of rps constant mode, 1: constant, 2: linear from num to max num, 3: poisson arrivals of num,
+		// 4: sine wave around num with the amplitude percent, 5: sawtooth from num to max num in steps every period
+		PacerType      int `json:"pacer_type" binding:"min=0,max=5"`
+		PacerPeriod    int `json:"pacer_period" binding:"min=0"` // seconds
+		PacerSteps     int `json:"pacer_steps" binding:"min=0"`
+		PacerAmplitude int `json:"pacer_amplitude" binding:"min=0,max=100"`
+
+		// load profile of stage mode, the stress time is the sum of the stage durations and
+		// the targets are the rps or concurrency of every task
+		Stages []Stage `json:"stages"`
+	}
+	Stage struct {
+		Duration int `json:"duration"` // seconds
+		Target   int `json:"target"`
+		// 1: linear from the target of the previous stage, 2: jump to the target at the beginning
+		Transition int `json:"transition"`
 	}
 	Task struct {
 		PlanId           uint64 `json:"-"`
@@ -29,14 +46,19 @@ type (
 		StressType       int    `json:"-"`
 		StressMode       int    `json:"-"`
 		StepIntervalTime int    `json:"-"`
+		PacerType        int    `json:"-"`
+		PacerPeriod      int    `json:"-"`
+		PacerSteps       int    `json:"-"`
+		PacerAmplitude   int    `json:"-"`
 		TaskName         string `json:"task_name" binding:"required"`
 		Num              int    `json:"num" binding:"required,min=1"`
 		MaxNum           int    `json:"max_num"`
 		StepNum          int    `json:"step_num"`
 		MaxConnections   int    `json:"max_connections" binding:"min=1"`
 		Url              string `json:"url" binding:"required"`
-		Method           string `json:"method" binding:"required,oneof=GET POST PUT DELETE"`
+		Method           string `json:"method" binding:"omitempty,oneof=GET POST PUT DELETE"`
 		Timeout          int    `json:"timeout" binding:"min=1,max=5000"`
+		ProtocolType     int    `json:"protocol_type"`
 
 		QueryEntry          []Entry           `json:"query"`
 		HeaderEntry         []Entry           `json:"header"`
@@ -50,11 +72,89 @@ type (
 		DisableKeepAlive   bool `json:"-"`
 		DisableRedirects   bool `json:"-"`
 		H2                 bool `json:"-"`
+		FastHttp           bool `json:"-"`
+		TlsVerify          bool `json:"-"`
+		TlsFreshHandshake  bool `json:"-"`
 
 		Options []string `json:"options"`
 
 		Proxy string `json:"proxy"`
 
+		// grpc full method name like package.Service/Method
+		GrpcMethod string `json:"grpc_method"`
+		// base64 encoded FileDescriptorSet, server reflection is used if empty
+		ProtoDescriptor string `json:"proto_descriptor"`
+
+		// json array of websocket messages sent in order, string elements are sent as they are
+		WsMessages string `json:"ws_messages"`
+		// json field to match the reply of a message, the next received message is the reply if empty
+		WsCorrelationField string `json:"ws_correlation_field"`
+		// seconds to hold the websocket connection open after all messages are replied
+		WsHoldTime int `json:"ws_hold_time" binding:"min=0"`
+
+		// parse the http response as a stream, 1: server-sent events, 2: chunked
+		StreamType int `json:"stream_type" binding:"min=0,max=2"`
+		// how the http response body is read, 1: discard, which is the same as size only, 2: size only,
+		// 3: compare the sha256 checksum with the body checksum in hex, 4: buffer. By default the body is
+		// buffered only if the response check script needs it, otherwise only its size is measured
+		BodyMode     int    `json:"body_mode" binding:"min=0,max=4"`
+		BodyChecksum string `json:"body_checksum"`
+
+		// tls settings, the server certificate is verified by the ca bundle, or by the system roots with the
+		// tlsVerify option, otherwise it is not verified. The pem material is stored and delivered encrypted.
+		// The versions are like 1.2, the cipher suites and the alpn protocols are comma separated
+		TlsCa           string `json:"tls_ca"`
+		TlsCert         string `json:"tls_cert"`
+		TlsKey          string `json:"tls_key"`
+		TlsServerName   string `json:"tls_server_name"`
+		TlsMinVersion   string `json:"tls_min_version" binding:"omitempty,oneof=1.0 1.1 1.2 1.3"`
+		TlsMaxVersion   string `json:"tls_max_version" binding:"omitempty,oneof=1.0 1.1 1.2 1.3"`
+		TlsCipherSuites string `json:"tls_cipher_suites"`
+		TlsAlpn         string `json:"tls_alpn"`
+
+		// the hosts mapped to the addresses like curl --resolve, one mapping per line in the form of
+		// host:port:addr[,addr]..., the host header and the sni are kept. The other hosts are resolved
+		// once per dns cache ttl in seconds if it is positive, otherwise per new connection
+		HostMapping string `json:"host_mapping"`
+		DnsCacheTtl int    `json:"dns_cache_ttl" binding:"min=0"`
+		// the comma separated local source addresses the peckers bind in turn instead of the ones of their
+		// config, the addresses should be assigned to every pecker of the task
+		SourceAddresses string `json:"source_addresses"`
+
+		// tcp and udp payload, the body is the payload template, 1: hex, 2: base64
+		PayloadEncoding int `json:"payload_encoding" binding:"min=0,max=2"`
+		// the response ends with the delimiter encoded like the payload, or has the expected length
+		ResponseDelimiter string `json:"response_delimiter"`
+		ResponseLength    int    `json:"response_length" binding:"min=0"`
+
+		// dns query type, the query names are the body or the bodies of the dynamic params
+		DnsQueryType string `json:"dns_query_type" binding:"omitempty,oneof=A AAAA SRV TXT"`
+
+		// json array of weighted redis commands and pipelines, like [{"command":["GET","user:{{id}}"],"weight":3}]
+		RedisCommands string `json:"redis_commands"`
+
+		// json array of the http steps run in order by every virtual user, relative step urls are
+		// resolved against the url, like [{"name":"login","method":"POST","url":"/login","extract":[...]}]
+		ScenarioSteps string `json:"scenario_steps"`
+
+		// think time between the iterations of a virtual user in concurrency mode, in milliseconds,
+		// 1: constant think time, 2: uniform between think time and max think time, 3: exponential with mean think time
+		ThinkTimeType int `json:"think_time_type" binding:"min=0,max=3"`
+		ThinkTime     int `json:"think_time" binding:"min=0"`
+		ThinkTimeMax  int `json:"think_time_max" binding:"min=0"`
+		// a virtual user starts an iteration at most every pacing time, in milliseconds
+		PacingTime int `json:"pacing_time" binding:"min=0"`
+
+		// max in-flight requests of every node in rps mode, no limit if zero. when the limit is reached,
+		// 1: skip the request, 2: queue the request for at most the queue timeout in milliseconds, then skip it
+		MaxInFlight          int `json:"max_in_flight" binding:"min=0"`
+		InFlightPolicy       int `json:"in_flight_policy" binding:"min=0,max=2"`
+		InFlightQueueTimeout int `json:"in_flight_queue_timeout" binding:"min=0"`
+
+		// stages of stage mode, the targets of a node are its nums
+		StageDurations   []int32 `json:"-"`
+		StageTransitions []int32 `json:"-"`
+
 		DynamicParams []DynamicParam `json:"-"`
 		nodes         []*BindNode
 	}
@@ -71,12 +171,17 @@ type (
 		num      int
 		Nums     []int32
 		nodeInfo *repo.Node
+		// the share of the pacer start and end rate of the node
+		pacerStartNum int
+		pacerEndNum   int
 	}
 	BindConn struct {
-		num      int
-		Nums     []int32
-		Addr     string
-		grpcConn *grpc.ClientConn
+		num           int
+		Nums          []int32
+		Addr          string
+		grpcConn      *grpc.ClientConn
+		pacerStartNum int
+		pacerEndNum   int
 	}
 	NodeInstanceCost struct {
 		instance         *registry.ServiceInstance
@@ -160,10 +265,55 @@ type (
 		DisableKeepAlive   int8   `json:"disable_keep_alive"`
 		DisableRedirects   int8   `json:"disable_redirects"`
 		H2                 int8   `json:"h_2"`
+		FastHttp           int8   `json:"fast_http"`
+		TlsVerify          int8   `json:"tls_verify"`
+		TlsFreshHandshake  int8   `json:"tls_fresh_handshake"`
 		Proxy              string `json:"proxy"`
 
 		MaxBodySize int64 `json:"max_body_size"`
 
+		GrpcMethod      string `json:"grpc_method"`
+		ProtoDescriptor string `json:"proto_descriptor"`
+
+		WsMessages         string `json:"ws_messages"`
+		WsCorrelationField string `json:"ws_correlation_field"`
+		WsHoldTime         int    `json:"ws_hold_time"`
+
+		StreamType   int    `json:"stream_type"`
+		BodyMode     int    `json:"body_mode"`
+		BodyChecksum string `json:"body_checksum"`
+
+		TlsCa           string `json:"tls_ca"`
+		TlsCert         string `json:"tls_cert"`
+		TlsKey          string `json:"tls_key"`
+		TlsServerName   string `json:"tls_server_name"`
+		TlsMinVersion   string `json:"tls_min_version"`
+		TlsMaxVersion   string `json:"tls_max_version"`
+		TlsCipherSuites string `json:"tls_cipher_suites"`
+		TlsAlpn         string `json:"tls_alpn"`
+
+		HostMapping string `json:"host_mapping"`
+		DnsCacheTtl int    `json:"dns_cache_ttl"`
+
+		SourceAddresses string `json:"source_addresses"`
+
+		PayloadEncoding   int    `json:"payload_encoding"`
+		ResponseDelimiter string `json:"response_delimiter"`
+		ResponseLength    int    `json:"response_length"`
+
+		DnsQueryType  string `json:"dns_query_type"`
+		RedisCommands string `json:"redis_commands"`
+		ScenarioSteps string `json:"scenario_steps"`
+
+		ThinkTimeType int `json:"think_time_type"`
+		ThinkTime     int `json:"think_time"`
+		ThinkTimeMax  int `json:"think_time_max"`
+		PacingTime    int `json:"pacing_time"`
+
+		MaxInFlight          int `json:"max_in_flight"`
+		InFlightPolicy       int `json:"in_flight_policy"`
+		InFlightQueueTimeout int `json:"in_flight_queue_timeout"`
+
 		Nodes string `json:"nodes"`
 
 		MetricsUrl string `json:"metrics_url"`
@@ -183,6 +333,7 @@ type (
 		TaskId uint64
 		Rps    float64
 
+		// the latencies are in microseconds
 		AvgTotal      int64
 		Fastest       int64
 		Slowest       int64
@@ -204,6 +355,61 @@ type (
 		LatencyDistribution []LatencyDistribution
 		ErrorDist           map[string]int64
 		BodyCheckResultMap  map[string]int64
+
+		DisconnectCount              int64
+		HandshakeLatencyDistribution []LatencyDistribution
+		MessageLatencyDistribution   []LatencyDistribution
+
+		FirstByteLatencyDistribution      []LatencyDistribution
+		FirstEventLatencyDistribution     []LatencyDistribution
+		EventGapLatencyDistribution       []LatencyDistribution
+		StreamDurationLatencyDistribution []LatencyDistribution
+
+		ConnectLatencyDistribution []LatencyDistribution
+		WriteLatencyDistribution   []LatencyDistribution
+		ReadLatencyDistribution    []LatencyDistribution
+
+		DnsLatencyDistribution      []LatencyDistribution
+		TlsLatencyDistribution      []LatencyDistribution
+		WaitLatencyDistribution     []LatencyDistribution
+		TransferLatencyDistribution []LatencyDistribution
+
+		CommandCount               map[string]int64
+		CommandLatencyDistribution map[string][]LatencyDistribution
+
+		StepCount               map[string]int64
+		StepErrorCount          map[string]int64
+		StepLatencyDistribution map[string][]LatencyDistribution
+
+		ResponseLatencyDistribution []LatencyDistribution
+		LateCount                   int64
+
+		SkippedCount int64
+
+		// the throughput is in MB/s and the sizes are in bytes
+		RequestBytesTotal        int64
+		ResponseBytesTotal       int64
+		UploadThroughput         float64
+		DownloadThroughput       float64
+		ResponseSizeDistribution []SizeDistribution
+
+		// the tls handshakes of the new connections, the resumed ones and the rate of them, and the
+		// handshake counts of the negotiated versions and cipher suites
+		TlsHandshakeCount int64
+		TlsResumedCount   int64
+		TlsResumptionRate float64
+		TlsVersionCount   map[string]int64
+		TlsCipherCount    map[string]int64
+
+		// the new and reused connections got by the requests, the rate of the reused ones and the peak
+		// open connections, which is the sum of the peaks of the nodes, and the ones of every node
+		NewConnCount          int64
+		ReusedConnCount       int64
+		ConnReuseRate         float64
+		PeakOpenConnCount     int64
+		NodeNewConnCount      map[string]int64
+		NodeReusedConnCount   map[string]int64
+		NodePeakOpenConnCount map[string]int64
 	}
 
 	LatencyDistribution struct {
@@ -213,6 +419,12 @@ type (
 		LatencyMs     string
 	}
 
+	SizeDistribution struct {
+		Percentage    float64
+		Size          int64
+		PercentageStr string
+	}
+
 	Bucket struct {
 		Mark      int
 		Count     int64
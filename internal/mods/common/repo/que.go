@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"github.com/peckfly/gopeck/pkg/histogram"
 	"time"
 )
 
@@ -33,20 +34,123 @@ type (
 		TimeStamp             int64         `json:"timestamp"`
 		Stop                  bool          `json:"stop"`
 		BodyCheckResult       string        `json:"body_check_result"`
+
+		// websocket phases: handshake latency, round-trip latency of every message and unexpected disconnect
+		HandshakeDuration time.Duration   `json:"handshake_duration"`
+		MessageDurations  []time.Duration `json:"message_durations"`
+		Disconnected      bool            `json:"disconnected"`
+
+		// streaming response phases: time to first byte and first event since request start,
+		// the gaps between events and the duration from first byte to end of stream
+		FirstByteDuration  time.Duration   `json:"first_byte_duration"`
+		FirstEventDuration time.Duration   `json:"first_event_duration"`
+		EventGaps          []time.Duration `json:"event_gaps"`
+		StreamDuration     time.Duration   `json:"stream_duration"`
+
+		// socket phases: connect latency of new connections, write latency and read latency after written
+		ConnectDuration time.Duration `json:"connect_duration"`
+		WriteDuration   time.Duration `json:"write_duration"`
+		ReadDuration    time.Duration `json:"read_duration"`
+
+		// http phases: dns lookup, tls handshake, server wait from request written to first response byte
+		// and transfer of the response, the tcp connect and request write phases are the socket ones
+		DnsDuration      time.Duration `json:"dns_duration"`
+		TlsDuration      time.Duration `json:"tls_duration"`
+		WaitDuration     time.Duration `json:"wait_duration"`
+		TransferDuration time.Duration `json:"transfer_duration"`
+
+		// tls handshake of a new connection: whether the session was resumed, the negotiated version and cipher
+		TlsHandshake bool   `json:"tls_handshake"`
+		TlsResumed   bool   `json:"tls_resumed"`
+		TlsVersion   string `json:"tls_version"`
+		TlsCipher    string `json:"tls_cipher"`
+
+		// whether the request got a new or a reused connection, and the open connections of the executor
+		NewConn    bool  `json:"new_conn"`
+		ReusedConn bool  `json:"reused_conn"`
+		OpenConns  int64 `json:"open_conns"`
+
+		// bytes on the wire of the request and the response including the headers, the response body is
+		// counted as it is transferred, e.g. compressed, while the response content length is the body read
+		RequestBytes  int64 `json:"request_bytes"`
+		ResponseBytes int64 `json:"response_bytes"`
+
+		// rps mode: the response time from the intended send time of the pacer schedule, which includes the
+		// delay of sending behind the schedule, and whether the request was sent late
+		ResponseDuration time.Duration `json:"response_duration"`
+		Late             bool          `json:"late"`
+
+		// Command is the name of the redis command or pipeline, its latency is the duration
+		Command string `json:"command"`
+
+		// Steps are the results of the scenario steps in order
+		Steps []StepResult `json:"steps"`
+	}
+
+	StepResult struct {
+		Name       string        `json:"name"`
+		Duration   time.Duration `json:"duration"`
+		StatusCode int           `json:"status_code"`
+		Err        string        `json:"err"`
 	}
 
 	Aggregate struct {
-		Interval                   int              `json:"interval"`
-		PlanId                     uint64           `json:"plan_id"`
-		TaskId                     uint64           `json:"task_id"`
-		Timestamp                  int64            `json:"timestamp"`
-		TotalNum                   int64            `json:"total_num"`
-		TotalResponseContentLength int64            `json:"total_response_content_length"`
-		DurationMap                map[int32]int64  `json:"duration_map"`
-		StatusMap                  map[int32]int64  `json:"status_map"`
-		ErrorMap                   map[string]int64 `json:"error_map"`
-		BodyCheckResultMap         map[string]int64 `json:"body_check_result_map"`
-		Stop                       bool             `json:"stop"`
+		Interval                   int                 `json:"interval"`
+		PlanId                     uint64              `json:"plan_id"`
+		TaskId                     uint64              `json:"task_id"`
+		Timestamp                  int64               `json:"timestamp"`
+		TotalNum                   int64               `json:"total_num"`
+		TotalResponseContentLength int64               `json:"total_response_content_length"`
+		DurationMap                histogram.Histogram `json:"duration_map"`
+		StatusMap                  map[int32]int64     `json:"status_map"`
+		ErrorMap                   map[string]int64    `json:"error_map"`
+		BodyCheckResultMap         map[string]int64    `json:"body_check_result_map"`
+		HandshakeDurationMap       histogram.Histogram `json:"handshake_duration_map"`
+		MessageDurationMap         histogram.Histogram `json:"message_duration_map"`
+		DisconnectNum              int64               `json:"disconnect_num"`
+		FirstByteDurationMap       histogram.Histogram `json:"first_byte_duration_map"`
+		FirstEventDurationMap      histogram.Histogram `json:"first_event_duration_map"`
+		EventGapDurationMap        histogram.Histogram `json:"event_gap_duration_map"`
+		StreamDurationMap          histogram.Histogram `json:"stream_duration_map"`
+		ConnectDurationMap         histogram.Histogram `json:"connect_duration_map"`
+		WriteDurationMap           histogram.Histogram `json:"write_duration_map"`
+		ReadDurationMap            histogram.Histogram `json:"read_duration_map"`
+		DnsDurationMap             histogram.Histogram `json:"dns_duration_map"`
+		TlsDurationMap             histogram.Histogram `json:"tls_duration_map"`
+		WaitDurationMap            histogram.Histogram `json:"wait_duration_map"`
+		TransferDurationMap        histogram.Histogram `json:"transfer_duration_map"`
+		// CommandDurationMap is the duration map of every redis command name
+		CommandDurationMap map[string]histogram.Histogram `json:"command_duration_map"`
+		// StepDurationMap and StepErrorMap are the duration map and error count of every scenario step name
+		StepDurationMap map[string]histogram.Histogram `json:"step_duration_map"`
+		StepErrorMap    map[string]int64               `json:"step_error_map"`
+		Stop            bool                           `json:"stop"`
+		// ResponseDurationMap is the response time from the intended send time and LateNum is the count
+		// of the requests sent behind the schedule in rps mode
+		ResponseDurationMap histogram.Histogram `json:"response_duration_map"`
+		LateNum             int64               `json:"late_num"`
+		// SkippedNum is the count of the requests not sent for the max in-flight limit in rps mode
+		SkippedNum int64 `json:"skipped_num"`
+		// TotalRequestBytes and TotalResponseBytes are the bytes on the wire, ResponseSizeMap is the
+		// histogram of the response content lengths in bytes
+		TotalRequestBytes  int64               `json:"total_request_bytes"`
+		TotalResponseBytes int64               `json:"total_response_bytes"`
+		ResponseSizeMap    histogram.Histogram `json:"response_size_map"`
+		// TlsHandshakeNum and TlsResumedNum are the counts of the tls handshakes of the new connections and
+		// the resumed ones, TlsVersionMap and TlsCipherMap are the handshake counts of the negotiated ones
+		TlsHandshakeNum int64            `json:"tls_handshake_num"`
+		TlsResumedNum   int64            `json:"tls_resumed_num"`
+		TlsVersionMap   map[string]int64 `json:"tls_version_map"`
+		TlsCipherMap    map[string]int64 `json:"tls_cipher_map"`
+		// NewConnMap, ReusedConnMap and PeakOpenConnMap are the new and reused connections and the peak
+		// open connections of every node by the node address
+		NewConnMap      map[string]int64 `json:"new_conn_map"`
+		ReusedConnMap   map[string]int64 `json:"reused_conn_map"`
+		PeakOpenConnMap map[string]int64 `json:"peak_open_conn_map"`
+		// MaxDuration is the max value of the histograms, the longer durations are recorded as it if positive
+		MaxDuration time.Duration `json:"-"`
+		// Node is the address of the node adding the results, the connection counts are kept by it
+		Node string `json:"-"`
 	}
 )
 
@@ -54,9 +158,213 @@ func NewAggeRate() *Aggregate {
 	return &Aggregate{
 		TotalNum:                   int64(0),
 		TotalResponseContentLength: int64(0),
-		DurationMap:                make(map[int32]int64),
+		DurationMap:                histogram.New(),
 		StatusMap:                  make(map[int32]int64),
 		ErrorMap:                   make(map[string]int64),
 		BodyCheckResultMap:         make(map[string]int64),
+		HandshakeDurationMap:       histogram.New(),
+		MessageDurationMap:         histogram.New(),
+		FirstByteDurationMap:       histogram.New(),
+		FirstEventDurationMap:      histogram.New(),
+		EventGapDurationMap:        histogram.New(),
+		StreamDurationMap:          histogram.New(),
+		ConnectDurationMap:         histogram.New(),
+		WriteDurationMap:           histogram.New(),
+		ReadDurationMap:            histogram.New(),
+		DnsDurationMap:             histogram.New(),
+		TlsDurationMap:             histogram.New(),
+		WaitDurationMap:            histogram.New(),
+		TransferDurationMap:        histogram.New(),
+		CommandDurationMap:         make(map[string]histogram.Histogram),
+		StepDurationMap:            make(map[string]histogram.Histogram),
+		StepErrorMap:               make(map[string]int64),
+		ResponseDurationMap:        histogram.New(),
+		ResponseSizeMap:            histogram.New(),
+		TlsVersionMap:              make(map[string]int64),
+		TlsCipherMap:               make(map[string]int64),
+		NewConnMap:                 make(map[string]int64),
+		ReusedConnMap:              make(map[string]int64),
+		PeakOpenConnMap:            make(map[string]int64),
+	}
+}
+
+// Add adds the result of one request to the aggregate
+func (a *Aggregate) Add(result *Result) {
+	a.TotalNum++
+	a.TotalResponseContentLength += result.ResponseContentLength
+	a.record(a.DurationMap, result.Duration)
+	a.StatusMap[int32(result.StatusCode)]++
+	a.ErrorMap[result.Err]++
+	a.BodyCheckResultMap[result.BodyCheckResult]++
+	if result.HandshakeDuration > 0 {
+		a.record(a.HandshakeDurationMap, result.HandshakeDuration)
+	}
+	for _, duration := range result.MessageDurations {
+		a.record(a.MessageDurationMap, duration)
+	}
+	if result.Disconnected {
+		a.DisconnectNum++
+	}
+	if result.FirstByteDuration > 0 {
+		a.record(a.FirstByteDurationMap, result.FirstByteDuration)
+	}
+	if result.FirstEventDuration > 0 {
+		a.record(a.FirstEventDurationMap, result.FirstEventDuration)
+	}
+	for _, gap := range result.EventGaps {
+		a.record(a.EventGapDurationMap, gap)
+	}
+	if result.StreamDuration > 0 {
+		a.record(a.StreamDurationMap, result.StreamDuration)
+	}
+	if result.ConnectDuration > 0 {
+		a.record(a.ConnectDurationMap, result.ConnectDuration)
+	}
+	if result.WriteDuration > 0 {
+		a.record(a.WriteDurationMap, result.WriteDuration)
+	}
+	if result.ReadDuration > 0 {
+		a.record(a.ReadDurationMap, result.ReadDuration)
+	}
+	if result.DnsDuration > 0 {
+		a.record(a.DnsDurationMap, result.DnsDuration)
+	}
+	if result.TlsDuration > 0 {
+		a.record(a.TlsDurationMap, result.TlsDuration)
+	}
+	if result.WaitDuration > 0 {
+		a.record(a.WaitDurationMap, result.WaitDuration)
+	}
+	if result.TransferDuration > 0 {
+		a.record(a.TransferDurationMap, result.TransferDuration)
+	}
+	if len(result.Command) > 0 {
+		a.recordNamed(a.CommandDurationMap, result.Command, result.Duration)
+	}
+	for _, step := range result.Steps {
+		a.recordNamed(a.StepDurationMap, step.Name, step.Duration)
+		if len(step.Err) > 0 {
+			a.StepErrorMap[step.Name]++
+		}
+	}
+	if result.ResponseDuration > 0 {
+		a.record(a.ResponseDurationMap, result.ResponseDuration)
+	}
+	if result.Late {
+		a.LateNum++
+	}
+	a.TotalRequestBytes += result.RequestBytes
+	a.TotalResponseBytes += result.ResponseBytes
+	if len(result.Err) == 0 {
+		a.ResponseSizeMap.RecordValues(result.ResponseContentLength, 1)
+	}
+	if result.TlsHandshake {
+		a.TlsHandshakeNum++
+		if result.TlsResumed {
+			a.TlsResumedNum++
+		}
+		a.TlsVersionMap[result.TlsVersion]++
+		a.TlsCipherMap[result.TlsCipher]++
+	}
+	if result.NewConn {
+		a.NewConnMap[a.Node]++
+	}
+	if result.ReusedConn {
+		a.ReusedConnMap[a.Node]++
+	}
+	if result.OpenConns > a.PeakOpenConnMap[a.Node] {
+		a.PeakOpenConnMap[a.Node] = result.OpenConns
+	}
+}
+
+// record records the duration into the histogram, limited by the max duration
+func (a *Aggregate) record(h histogram.Histogram, duration time.Duration) {
+	if a.MaxDuration > 0 {
+		duration = min(duration, a.MaxDuration)
+	}
+	h.Record(duration)
+}
+
+// recordNamed records the duration into the histogram of the name
+func (a *Aggregate) recordNamed(m map[string]histogram.Histogram, name string, duration time.Duration) {
+	h, ok := m[name]
+	if !ok {
+		h = histogram.New()
+		m[name] = h
+	}
+	a.record(h, duration)
+}
+
+// Merge merges another aggregate into the aggregate
+func (a *Aggregate) Merge(o *Aggregate) {
+	a.TotalNum += o.TotalNum
+	a.TotalResponseContentLength += o.TotalResponseContentLength
+	a.DurationMap.Merge(o.DurationMap)
+	mergeCountMap(a.StatusMap, o.StatusMap)
+	mergeCountMap(a.ErrorMap, o.ErrorMap)
+	mergeCountMap(a.BodyCheckResultMap, o.BodyCheckResultMap)
+	a.HandshakeDurationMap.Merge(o.HandshakeDurationMap)
+	a.MessageDurationMap.Merge(o.MessageDurationMap)
+	a.DisconnectNum += o.DisconnectNum
+	a.FirstByteDurationMap.Merge(o.FirstByteDurationMap)
+	a.FirstEventDurationMap.Merge(o.FirstEventDurationMap)
+	a.EventGapDurationMap.Merge(o.EventGapDurationMap)
+	a.StreamDurationMap.Merge(o.StreamDurationMap)
+	a.ConnectDurationMap.Merge(o.ConnectDurationMap)
+	a.WriteDurationMap.Merge(o.WriteDurationMap)
+	a.ReadDurationMap.Merge(o.ReadDurationMap)
+	a.DnsDurationMap.Merge(o.DnsDurationMap)
+	a.TlsDurationMap.Merge(o.TlsDurationMap)
+	a.WaitDurationMap.Merge(o.WaitDurationMap)
+	a.TransferDurationMap.Merge(o.TransferDurationMap)
+	MergeNamedHistogram(a.CommandDurationMap, o.CommandDurationMap)
+	MergeNamedHistogram(a.StepDurationMap, o.StepDurationMap)
+	mergeCountMap(a.StepErrorMap, o.StepErrorMap)
+	a.ResponseDurationMap.Merge(o.ResponseDurationMap)
+	a.LateNum += o.LateNum
+	a.SkippedNum += o.SkippedNum
+	a.TotalRequestBytes += o.TotalRequestBytes
+	a.TotalResponseBytes += o.TotalResponseBytes
+	a.ResponseSizeMap.Merge(o.ResponseSizeMap)
+	a.TlsHandshakeNum += o.TlsHandshakeNum
+	a.TlsResumedNum += o.TlsResumedNum
+	mergeCountMap(a.TlsVersionMap, o.TlsVersionMap)
+	mergeCountMap(a.TlsCipherMap, o.TlsCipherMap)
+	mergeCountMap(a.NewConnMap, o.NewConnMap)
+	mergeCountMap(a.ReusedConnMap, o.ReusedConnMap)
+	MergeMaxMap(a.PeakOpenConnMap, o.PeakOpenConnMap)
+}
+
+// MergeNamedHistogram merges the histograms of every name of src into dst
+func MergeNamedHistogram(dst, src map[string]histogram.Histogram) {
+	for name, h := range src {
+		if _, ok := dst[name]; !ok {
+			dst[name] = histogram.New()
+		}
+		dst[name].Merge(h)
+	}
+}
+
+func mergeCountMap[K comparable](dst, src map[K]int64) {
+	for k, cnt := range src {
+		dst[k] += cnt
+	}
+}
+
+// MergeMaxMap merges the max value of every key of src into dst
+func MergeMaxMap[K comparable](dst, src map[K]int64) {
+	for k, v := range src {
+		if cur, ok := dst[k]; !ok || v > cur {
+			dst[k] = v
+		}
+	}
+}
+
+// SumMap returns the sum of the values of the map
+func SumMap[K comparable](m map[K]int64) int64 {
+	var sum int64
+	for _, v := range m {
+		sum += v
 	}
+	return sum
 }
@@ -17,6 +17,11 @@ type (
 		PlanName         string `gorm:"column:plan_name" json:"plan_name"`
 		StepIntervalTime int    `gorm:"column:step_interval_time" json:"step_interval_time"`
 		IntervalLen      int    `gorm:"column:interval_len" json:"interval_len"`
+		PacerType        int    `gorm:"column:pacer_type" json:"pacer_type"`
+		PacerPeriod      int    `gorm:"column:pacer_period" json:"pacer_period"`
+		PacerSteps       int    `gorm:"column:pacer_steps" json:"pacer_steps"`
+		PacerAmplitude   int    `gorm:"column:pacer_amplitude" json:"pacer_amplitude"`
+		Stages           string `gorm:"column:stages" json:"stages"`
 		CreateTime       int64  `gorm:"column:create_time" json:"create_time"`
 		UpdateTime       int64  `gorm:"column:update_time" json:"update_time"`
 	}
@@ -46,10 +51,56 @@ type (
 		DisableKeepAlive   int8   `gorm:"column:disable_keep_alive" json:"disable_keep_alive"`
 		DisableRedirects   int8   `gorm:"column:disable_redirects" json:"disable_redirects"`
 		H2                 int8   `gorm:"column:h_2" json:"h_2"`
+		FastHttp           int8   `gorm:"column:fast_http" json:"fast_http"`
+		TlsVerify          int8   `gorm:"column:tls_verify" json:"tls_verify"`
+		TlsFreshHandshake  int8   `gorm:"column:tls_fresh_handshake" json:"tls_fresh_handshake"`
 		Proxy              string `gorm:"column:proxy" json:"proxy"`
 
 		MaxBodySize int64 `gorm:"column:max_body_size" json:"max_body_size"`
 
+		GrpcMethod      string `gorm:"column:grpc_method" json:"grpc_method"`
+		ProtoDescriptor string `gorm:"column:proto_descriptor" json:"proto_descriptor"`
+
+		WsMessages         string `gorm:"column:ws_messages" json:"ws_messages"`
+		WsCorrelationField string `gorm:"column:ws_correlation_field" json:"ws_correlation_field"`
+		WsHoldTime         int    `gorm:"column:ws_hold_time" json:"ws_hold_time"`
+
+		StreamType   int    `gorm:"column:stream_type" json:"stream_type"`
+		BodyMode     int    `gorm:"column:body_mode" json:"body_mode"`
+		BodyChecksum string `gorm:"column:body_checksum" json:"body_checksum"`
+
+		// the pem material is encrypted
+		TlsCa           string `gorm:"column:tls_ca" json:"tls_ca"`
+		TlsCert         string `gorm:"column:tls_cert" json:"tls_cert"`
+		TlsKey          string `gorm:"column:tls_key" json:"tls_key"`
+		TlsServerName   string `gorm:"column:tls_server_name" json:"tls_server_name"`
+		TlsMinVersion   string `gorm:"column:tls_min_version" json:"tls_min_version"`
+		TlsMaxVersion   string `gorm:"column:tls_max_version" json:"tls_max_version"`
+		TlsCipherSuites string `gorm:"column:tls_cipher_suites" json:"tls_cipher_suites"`
+		TlsAlpn         string `gorm:"column:tls_alpn" json:"tls_alpn"`
+
+		HostMapping string `gorm:"column:host_mapping" json:"host_mapping"`
+		DnsCacheTtl int    `gorm:"column:dns_cache_ttl" json:"dns_cache_ttl"`
+
+		SourceAddresses string `gorm:"column:source_addresses" json:"source_addresses"`
+
+		PayloadEncoding   int    `gorm:"column:payload_encoding" json:"payload_encoding"`
+		ResponseDelimiter string `gorm:"column:response_delimiter" json:"response_delimiter"`
+		ResponseLength    int    `gorm:"column:response_length" json:"response_length"`
+
+		DnsQueryType  string `gorm:"column:dns_query_type" json:"dns_query_type"`
+		RedisCommands string `gorm:"column:redis_commands" json:"redis_commands"`
+		ScenarioSteps string `gorm:"column:scenario_steps" json:"scenario_steps"`
+
+		ThinkTimeType int `gorm:"column:think_time_type" json:"think_time_type"`
+		ThinkTime     int `gorm:"column:think_time" json:"think_time"`
+		ThinkTimeMax  int `gorm:"column:think_time_max" json:"think_time_max"`
+		PacingTime    int `gorm:"column:pacing_time" json:"pacing_time"`
+
+		MaxInFlight          int `gorm:"column:max_in_flight" json:"max_in_flight"`
+		InFlightPolicy       int `gorm:"column:in_flight_policy" json:"in_flight_policy"`
+		InFlightQueueTimeout int `gorm:"column:in_flight_queue_timeout" json:"in_flight_queue_timeout"`
+
 		// stress node list
 		Nodes string `gorm:"column:nodes" json:"nodes"`
 
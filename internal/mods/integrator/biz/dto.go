@@ -1,5 +1,7 @@
 package biz
 
+import "github.com/peckfly/gopeck/pkg/histogram"
+
 type (
 	Integrate struct {
 		PlanId           uint64
@@ -11,6 +13,8 @@ type (
 		StartTime        int64
 		IntervalLen      int32
 		UserId           int64
+		// durations of the stages in seconds, every stage is an interval in stage mode
+		StageDurations []int32
 	}
 
 	Task struct {
@@ -23,6 +27,7 @@ type (
 		TaskId uint64
 		Rps    float64
 
+		// the latencies are in microseconds
 		AvgTotal      int64
 		Fastest       int64
 		Slowest       int64
@@ -32,12 +37,87 @@ type (
 		NumRes        int64
 		TotalCostTime float64
 
-		TimeBuckets         []int64 `json:"-"`
+		DurationMap         histogram.Histogram `json:"-"`
 		StatusCodeDist      map[int]int64
 		Histogram           []Bucket
 		LatencyDistribution []LatencyDistribution
 		ErrorDist           map[string]int64
 		BodyCheckResultMap  map[string]int64
+
+		DisconnectCount              int64
+		HandshakeDurationMap         histogram.Histogram `json:"-"`
+		MessageDurationMap           histogram.Histogram `json:"-"`
+		HandshakeLatencyDistribution []LatencyDistribution
+		MessageLatencyDistribution   []LatencyDistribution
+
+		FirstByteDurationMap              histogram.Histogram `json:"-"`
+		FirstEventDurationMap             histogram.Histogram `json:"-"`
+		EventGapDurationMap               histogram.Histogram `json:"-"`
+		StreamDurationMap                 histogram.Histogram `json:"-"`
+		FirstByteLatencyDistribution      []LatencyDistribution
+		FirstEventLatencyDistribution     []LatencyDistribution
+		EventGapLatencyDistribution       []LatencyDistribution
+		StreamDurationLatencyDistribution []LatencyDistribution
+
+		ConnectDurationMap         histogram.Histogram `json:"-"`
+		WriteDurationMap           histogram.Histogram `json:"-"`
+		ReadDurationMap            histogram.Histogram `json:"-"`
+		ConnectLatencyDistribution []LatencyDistribution
+		WriteLatencyDistribution   []LatencyDistribution
+		ReadLatencyDistribution    []LatencyDistribution
+
+		DnsDurationMap              histogram.Histogram `json:"-"`
+		TlsDurationMap              histogram.Histogram `json:"-"`
+		WaitDurationMap             histogram.Histogram `json:"-"`
+		TransferDurationMap         histogram.Histogram `json:"-"`
+		DnsLatencyDistribution      []LatencyDistribution
+		TlsLatencyDistribution      []LatencyDistribution
+		WaitLatencyDistribution     []LatencyDistribution
+		TransferLatencyDistribution []LatencyDistribution
+
+		CommandDurationMap         map[string]histogram.Histogram `json:"-"`
+		CommandCount               map[string]int64
+		CommandLatencyDistribution map[string][]LatencyDistribution
+
+		StepDurationMap         map[string]histogram.Histogram `json:"-"`
+		StepCount               map[string]int64
+		StepErrorCount          map[string]int64
+		StepLatencyDistribution map[string][]LatencyDistribution
+
+		// the response time from the intended send time and the count of late sends in rps mode
+		ResponseDurationMap         histogram.Histogram `json:"-"`
+		ResponseLatencyDistribution []LatencyDistribution
+		LateCount                   int64
+
+		// the count of the requests not sent for the max in-flight limit in rps mode
+		SkippedCount int64
+
+		// the bytes on the wire and the throughput in MB/s of both directions, the request bytes are
+		// estimated by the request body if the protocol does not measure them
+		RequestBytesTotal        int64
+		ResponseBytesTotal       int64
+		UploadThroughput         float64
+		DownloadThroughput       float64
+		ResponseSizeMap          histogram.Histogram `json:"-"`
+		ResponseSizeDistribution []SizeDistribution
+
+		// the tls handshakes of the new connections, the resumed ones and the rate of them, and the
+		// handshake counts of the negotiated versions and cipher suites
+		TlsHandshakeCount int64
+		TlsResumedCount   int64
+		TlsResumptionRate float64
+		TlsVersionCount   map[string]int64
+		TlsCipherCount    map[string]int64
+
+		// the new and reused connections got by the requests, the rate of the reused ones and the peak
+		// open connections, which is the sum of the peaks of the nodes, and the ones of every node
+		NewConnCount          int64
+		ReusedConnCount       int64
+		ConnReuseRate         float64
+		PeakOpenConnCount     int64
+		NodeNewConnCount      map[string]int64
+		NodeReusedConnCount   map[string]int64
+		NodePeakOpenConnCount map[string]int64
 	}
 
 	LatencyDistribution struct {
@@ -47,6 +127,12 @@ type (
 		LatencyMs     string
 	}
 
+	SizeDistribution struct {
+		Percentage    float64
+		Size          int64
+		PercentageStr string
+	}
+
 	Bucket struct {
 		Mark      int
 		Count     int64
@@ -7,9 +7,11 @@ import (
 	"github.com/panjf2000/ants/v2"
 	"github.com/peckfly/gopeck/internal/mods/common/repo"
 	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/histogram"
 	"github.com/peckfly/gopeck/pkg/log/logc"
 	"go.uber.org/zap"
 	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -17,14 +19,12 @@ import (
 )
 
 const (
-	// MillisecondBucket todo use timeout to calculate( if timeout smaller MillisecondBucket use it!)
-	MillisecondBucket            int = 5000
-	bucketNum                        = 9
-	PopWaitSecond                    = 10 * time.Second
-	EmptyPopSleepTime                = 50 * time.Millisecond
-	AggregationEmptyPopSleepTime     = 500 * time.Millisecond
-	ReportGoroutineNum               = 3
-	KST                              = 3
+	bucketNum                    = 9
+	PopWaitSecond                = 10 * time.Second
+	EmptyPopSleepTime            = 50 * time.Millisecond
+	AggregationEmptyPopSleepTime = 500 * time.Millisecond
+	ReportGoroutineNum           = 3
+	KST                          = 3
 )
 
 type IntegratorUsecase struct {
@@ -71,14 +71,11 @@ func (s *IntegratorUsecase) calcTask(ctx context.Context, task *Task, planId uin
 		if !ok {
 			return
 		}
-		timeBuckets := make([]int64, MillisecondBucket+1)
-		for duration, cnt := range bar.DurationMap {
-			timeBuckets[duration] += cnt
-		}
-		latencyDistribution := calculateLatencyDistribution(bar.TotalNum, timeBuckets)
-		latencyMap := make(map[string]int32)
+		latencyDistribution := calculateLatencyDistribution(bar.DurationMap)
+		newConnNum, reusedConnNum := repo.SumMap(bar.NewConnMap), repo.SumMap(bar.ReusedConnMap)
+		latencyMap := make(map[string]int64)
 		for _, lat := range latencyDistribution {
-			latencyMap[fmt.Sprintf("%.2f", lat.Percentage)] = int32(lat.Latency)
+			latencyMap[fmt.Sprintf("%.2f", lat.Percentage)] = int64(lat.Latency)
 		}
 		err := s.reporterRepository.Report(ctx, []*Report{
 			{
@@ -93,6 +90,42 @@ func (s *IntegratorUsecase) calcTask(ctx context.Context, task *Task, planId uin
 				ErrorMap:                   bar.ErrorMap,
 				BodyCheckResultMap:         bar.BodyCheckResultMap,
 				LatencyMap:                 latencyMap,
+				HandshakeDurationMap:       bar.HandshakeDurationMap,
+				MessageDurationMap:         bar.MessageDurationMap,
+				DisconnectNum:              bar.DisconnectNum,
+				FirstByteDurationMap:       bar.FirstByteDurationMap,
+				FirstEventDurationMap:      bar.FirstEventDurationMap,
+				EventGapDurationMap:        bar.EventGapDurationMap,
+				StreamDurationMap:          bar.StreamDurationMap,
+				ConnectDurationMap:         bar.ConnectDurationMap,
+				WriteDurationMap:           bar.WriteDurationMap,
+				ReadDurationMap:            bar.ReadDurationMap,
+				DnsDurationMap:             bar.DnsDurationMap,
+				TlsDurationMap:             bar.TlsDurationMap,
+				WaitDurationMap:            bar.WaitDurationMap,
+				TransferDurationMap:        bar.TransferDurationMap,
+				CommandDurationMap:         bar.CommandDurationMap,
+				StepDurationMap:            bar.StepDurationMap,
+				StepErrorMap:               bar.StepErrorMap,
+				ResponseDurationMap:        bar.ResponseDurationMap,
+				LateNum:                    bar.LateNum,
+				SkippedNum:                 bar.SkippedNum,
+				TotalRequestBytes:          bar.TotalRequestBytes,
+				TotalResponseBytes:         bar.TotalResponseBytes,
+				UploadThroughput:           throughput(bar.TotalRequestBytes, 1),
+				DownloadThroughput:         throughput(bar.TotalResponseBytes, 1),
+				ResponseSizeMap:            bar.ResponseSizeMap,
+				TlsHandshakeNum:            bar.TlsHandshakeNum,
+				TlsResumedNum:              bar.TlsResumedNum,
+				TlsVersionMap:              bar.TlsVersionMap,
+				TlsCipherMap:               bar.TlsCipherMap,
+				NewConnNum:                 newConnNum,
+				ReusedConnNum:              reusedConnNum,
+				ConnReuseRate:              reuseRate(newConnNum, reusedConnNum),
+				PeakOpenConnNum:            repo.SumMap(bar.PeakOpenConnMap),
+				NewConnMap:                 bar.NewConnMap,
+				ReusedConnMap:              bar.ReusedConnMap,
+				PeakOpenConnMap:            bar.PeakOpenConnMap,
 			}})
 		if err != nil {
 			logc.Error(ctx, "report error", zap.Error(err))
@@ -121,20 +154,7 @@ func (s *IntegratorUsecase) calcTask(ctx context.Context, task *Task, planId uin
 			car.Timestamp = timestamp
 			tars[timestamp] = car
 		}
-		car.TotalNum += result.TotalNum
-		car.TotalResponseContentLength += result.TotalResponseContentLength
-		for duration, cnt := range result.DurationMap {
-			car.DurationMap[duration] += cnt
-		}
-		for statusCode, cnt := range result.StatusMap {
-			car.StatusMap[statusCode] += cnt
-		}
-		for errStr, cnt := range result.ErrorMap {
-			car.ErrorMap[errStr] += cnt
-		}
-		for bodyCheckResult, cnt := range result.BodyCheckResultMap {
-			car.BodyCheckResultMap[bodyCheckResult] += cnt
-		}
+		car.Merge(result)
 		if len(tars) > KST {
 			minTimestamp := int64(math.MaxInt64)
 			var bar *repo.Aggregate
@@ -208,11 +228,33 @@ func (s *IntegratorUsecase) aggregationStat(ctx context.Context, task *Task, in
 	rs := make([]Summary, intervalLen)
 	for i := range rs {
 		rs[i].TaskId = task.TaskId
-		rs[i].Fastest = int64(MillisecondBucket)
 		rs[i].ErrorDist = make(map[string]int64)
 		rs[i].StatusCodeDist = make(map[int]int64)
-		rs[i].TimeBuckets = make([]int64, MillisecondBucket+1)
+		rs[i].DurationMap = histogram.New()
 		rs[i].BodyCheckResultMap = make(map[string]int64)
+		rs[i].HandshakeDurationMap = histogram.New()
+		rs[i].MessageDurationMap = histogram.New()
+		rs[i].FirstByteDurationMap = histogram.New()
+		rs[i].FirstEventDurationMap = histogram.New()
+		rs[i].EventGapDurationMap = histogram.New()
+		rs[i].StreamDurationMap = histogram.New()
+		rs[i].ConnectDurationMap = histogram.New()
+		rs[i].WriteDurationMap = histogram.New()
+		rs[i].ReadDurationMap = histogram.New()
+		rs[i].DnsDurationMap = histogram.New()
+		rs[i].TlsDurationMap = histogram.New()
+		rs[i].WaitDurationMap = histogram.New()
+		rs[i].TransferDurationMap = histogram.New()
+		rs[i].CommandDurationMap = make(map[string]histogram.Histogram)
+		rs[i].StepDurationMap = make(map[string]histogram.Histogram)
+		rs[i].StepErrorCount = make(map[string]int64)
+		rs[i].ResponseDurationMap = histogram.New()
+		rs[i].ResponseSizeMap = histogram.New()
+		rs[i].TlsVersionCount = make(map[string]int64)
+		rs[i].TlsCipherCount = make(map[string]int64)
+		rs[i].NodeNewConnCount = make(map[string]int64)
+		rs[i].NodeReusedConnCount = make(map[string]int64)
+		rs[i].NodePeakOpenConnCount = make(map[string]int64)
 	}
 	start := time.Now()
 	for {
@@ -230,17 +272,7 @@ func (s *IntegratorUsecase) aggregationStat(ctx context.Context, task *Task, in
 			continue
 		}
 		i := result.Interval
-		for costMs, cnt := range result.DurationMap {
-			if cnt < 0 {
-				continue
-			}
-			atomic.AddInt64(&rs[i].NumRes, cnt)
-			du := int64(costMs)
-			rs[i].Slowest = max(rs[i].Slowest, du)
-			rs[i].Fastest = min(rs[i].Fastest, du)
-			atomic.AddInt64(&rs[i].TimeBuckets[du], cnt)
-			atomic.AddInt64(&rs[i].AvgTotal, du*cnt)
-		}
+		rs[i].DurationMap.Merge(result.DurationMap)
 		for errorInfo, cnt := range result.ErrorMap {
 			if len(errorInfo) > 0 {
 				atomic.AddInt64(&rs[i].ErrorCount, cnt)
@@ -253,6 +285,46 @@ func (s *IntegratorUsecase) aggregationStat(ctx context.Context, task *Task, in
 		for checkResult, cnt := range result.BodyCheckResultMap {
 			rs[i].BodyCheckResultMap[checkResult] += cnt
 		}
+		rs[i].HandshakeDurationMap.Merge(result.HandshakeDurationMap)
+		rs[i].MessageDurationMap.Merge(result.MessageDurationMap)
+		rs[i].DisconnectCount += result.DisconnectNum
+		rs[i].FirstByteDurationMap.Merge(result.FirstByteDurationMap)
+		rs[i].FirstEventDurationMap.Merge(result.FirstEventDurationMap)
+		rs[i].EventGapDurationMap.Merge(result.EventGapDurationMap)
+		rs[i].StreamDurationMap.Merge(result.StreamDurationMap)
+		rs[i].ConnectDurationMap.Merge(result.ConnectDurationMap)
+		rs[i].WriteDurationMap.Merge(result.WriteDurationMap)
+		rs[i].ReadDurationMap.Merge(result.ReadDurationMap)
+		rs[i].DnsDurationMap.Merge(result.DnsDurationMap)
+		rs[i].TlsDurationMap.Merge(result.TlsDurationMap)
+		rs[i].WaitDurationMap.Merge(result.WaitDurationMap)
+		rs[i].TransferDurationMap.Merge(result.TransferDurationMap)
+		repo.MergeNamedHistogram(rs[i].CommandDurationMap, result.CommandDurationMap)
+		repo.MergeNamedHistogram(rs[i].StepDurationMap, result.StepDurationMap)
+		for step, cnt := range result.StepErrorMap {
+			rs[i].StepErrorCount[step] += cnt
+		}
+		rs[i].ResponseDurationMap.Merge(result.ResponseDurationMap)
+		rs[i].LateCount += result.LateNum
+		rs[i].SkippedCount += result.SkippedNum
+		rs[i].RequestBytesTotal += result.TotalRequestBytes
+		rs[i].ResponseBytesTotal += result.TotalResponseBytes
+		rs[i].ResponseSizeMap.Merge(result.ResponseSizeMap)
+		rs[i].TlsHandshakeCount += result.TlsHandshakeNum
+		rs[i].TlsResumedCount += result.TlsResumedNum
+		for version, cnt := range result.TlsVersionMap {
+			rs[i].TlsVersionCount[version] += cnt
+		}
+		for cipher, cnt := range result.TlsCipherMap {
+			rs[i].TlsCipherCount[cipher] += cnt
+		}
+		for node, cnt := range result.NewConnMap {
+			rs[i].NodeNewConnCount[node] += cnt
+		}
+		for node, cnt := range result.ReusedConnMap {
+			rs[i].NodeReusedConnCount[node] += cnt
+		}
+		repo.MergeMaxMap(rs[i].NodePeakOpenConnCount, result.PeakOpenConnMap)
 		if result.TotalResponseContentLength > 0 {
 			atomic.AddInt64(&rs[i].SizeTotal, result.TotalResponseContentLength)
 		}
@@ -264,8 +336,14 @@ func (s *IntegratorUsecase) aggregationStat(ctx context.Context, task *Task, in
 	err := s.queRepository.AggregateClear(ctx, task.TaskId)
 	logc.Info(ctx, "aggregation task collect done, start to calculate", zap.Uint64("PlanId", planId), zap.Uint64("TaskId", task.TaskId))
 	for i := range rs {
+		if rs[i].RequestBytesTotal == 0 {
+			// the request bytes are not measured by the protocol, they are estimated by the request body
+			rs[i].RequestBytesTotal = rs[i].DurationMap.TotalCount() * int64(task.RequestContentLength)
+		}
 		if int32(enums.Step) == in.StressMode {
 			latencyCalculate(&rs[i], int(in.StepIntervalTime))
+		} else if int32(enums.Stage) == in.StressMode && i < len(in.StageDurations) {
+			latencyCalculate(&rs[i], int(in.StageDurations[i]))
 		} else {
 			latencyCalculate(&rs[i], stressTime)
 		}
@@ -280,10 +358,44 @@ func (s *IntegratorUsecase) aggregationStat(ctx context.Context, task *Task, in
 // latencyCalculate calculate latency distribution and buckets histogram
 func latencyCalculate(r *Summary, time int) {
 	r.TotalCostTime = float64(time)
+	r.NumRes = r.DurationMap.TotalCount()
 	r.Rps = formatDecimal(float64(r.NumRes) / r.TotalCostTime) // actual using all request response time?
-	r.Average = formatDecimal(float64(r.AvgTotal / r.NumRes))  // avg cost time
+	r.Fastest = r.DurationMap.Min()
+	r.Slowest = r.DurationMap.Max()
+	mean := r.DurationMap.Mean()
+	r.Average = formatDecimal(mean) // avg cost time
+	r.AvgTotal = int64(mean * float64(r.NumRes))
 
-	r.LatencyDistribution = calculateLatencyDistribution(r.NumRes, r.TimeBuckets)
+	r.LatencyDistribution = calculateLatencyDistribution(r.DurationMap)
+	r.HandshakeLatencyDistribution = calculateLatencyDistribution(r.HandshakeDurationMap)
+	r.MessageLatencyDistribution = calculateLatencyDistribution(r.MessageDurationMap)
+	r.FirstByteLatencyDistribution = calculateLatencyDistribution(r.FirstByteDurationMap)
+	r.FirstEventLatencyDistribution = calculateLatencyDistribution(r.FirstEventDurationMap)
+	r.EventGapLatencyDistribution = calculateLatencyDistribution(r.EventGapDurationMap)
+	r.StreamDurationLatencyDistribution = calculateLatencyDistribution(r.StreamDurationMap)
+	r.ConnectLatencyDistribution = calculateLatencyDistribution(r.ConnectDurationMap)
+	r.WriteLatencyDistribution = calculateLatencyDistribution(r.WriteDurationMap)
+	r.ReadLatencyDistribution = calculateLatencyDistribution(r.ReadDurationMap)
+	r.DnsLatencyDistribution = calculateLatencyDistribution(r.DnsDurationMap)
+	r.TlsLatencyDistribution = calculateLatencyDistribution(r.TlsDurationMap)
+	r.WaitLatencyDistribution = calculateLatencyDistribution(r.WaitDurationMap)
+	r.TransferLatencyDistribution = calculateLatencyDistribution(r.TransferDurationMap)
+	r.CommandCount, r.CommandLatencyDistribution = calculateNamedDurationMapDistribution(r.CommandDurationMap)
+	r.StepCount, r.StepLatencyDistribution = calculateNamedDurationMapDistribution(r.StepDurationMap)
+	r.ResponseLatencyDistribution = calculateLatencyDistribution(r.ResponseDurationMap)
+	r.UploadThroughput = throughput(r.RequestBytesTotal, r.TotalCostTime)
+	r.DownloadThroughput = throughput(r.ResponseBytesTotal, r.TotalCostTime)
+	r.ResponseSizeDistribution = calculateSizeDistribution(r.ResponseSizeMap)
+	r.NewConnCount = repo.SumMap(r.NodeNewConnCount)
+	r.ReusedConnCount = repo.SumMap(r.NodeReusedConnCount)
+	r.ConnReuseRate = reuseRate(r.NewConnCount, r.ReusedConnCount)
+	r.PeakOpenConnCount = repo.SumMap(r.NodePeakOpenConnCount)
+	if r.TlsHandshakeCount > 0 {
+		r.TlsResumptionRate = formatDecimal(float64(r.TlsResumedCount) / float64(r.TlsHandshakeCount))
+	}
+	if r.NumRes == 0 {
+		return
+	}
 	buckets := make([]int, bucketNum+1)
 	counts := make([]int64, bucketNum+1)
 	bs := float64(r.Slowest-r.Fastest) / float64(bucketNum)
@@ -291,59 +403,86 @@ func latencyCalculate(r *Summary, time int) {
 		buckets[i] = int(float64(r.Fastest) + bs*float64(i))
 	}
 	buckets[bucketNum] = int(r.Slowest)
-	var bi int
-	for ms := 0; ms <= MillisecondBucket; {
-		if ms <= buckets[bi] {
-			counts[bi] += r.TimeBuckets[ms]
-			ms++
-		} else if bi < len(buckets)-1 {
-			bi++
-		} else {
-			break
-		}
+	for v, cnt := range r.DurationMap {
+		// the bucket value of the histogram is the lowest value of it, so it is counted in the first mark not less than it
+		bi := sort.SearchInts(buckets, int(v))
+		counts[min(bi, bucketNum)] += cnt
 	}
 	r.Histogram = make([]Bucket, len(buckets))
-	totalDecimal := float64(0)
 	for i := 0; i < len(buckets); i++ {
-		decimal := formatDecimal(float64(counts[i]) / float64(r.NumRes))
-		totalDecimal += decimal
 		r.Histogram[i] = Bucket{
 			Mark:      buckets[i],
 			Count:     counts[i],
-			Frequency: decimal,
+			Frequency: formatDecimal(float64(counts[i]) / float64(r.NumRes)),
 		}
 	}
 }
 
-// calculateLatencyDistribution calculate latency distribution with time buckets and total number of responses
-func calculateLatencyDistribution(totalNumRes int64, timeBuckets []int64) []LatencyDistribution {
-	pcs := []float64{100, 250, 500, 750, 900, 950, 990, 999}
-	data := make([]int, len(pcs))
-	cur := int64(0)
-	idx := 0
-	for ms := 0; ms <= MillisecondBucket; ms++ {
-		if timeBuckets[ms] <= 0 {
-			continue
-		}
-		cur += timeBuckets[ms]
-		for idx < len(pcs) && float64(cur)*float64(1000)/float64(totalNumRes) >= pcs[idx] {
-			data[idx] = ms
-			idx++
-		}
+// calculateLatencyDistribution calculate latency distribution of a microsecond histogram
+func calculateLatencyDistribution(h histogram.Histogram) []LatencyDistribution {
+	if h.TotalCount() == 0 {
+		return nil
 	}
+	pcs := []float64{10, 25, 50, 75, 90, 95, 99, 99.9}
+	values := h.ValuesAtPercentiles(pcs)
 	latencyDistribution := make([]LatencyDistribution, len(pcs))
 	for i := 0; i < len(pcs); i++ {
-		if data[i] > 0 {
-			latencyDistribution[i] = LatencyDistribution{Percentage: float64(pcs[i]) / 10,
-				Latency:       data[i],
-				PercentageStr: fmt.Sprintf("%.1f", formatDecimal(float64(pcs[i])/10)) + "%",
-				LatencyMs:     strconv.Itoa(data[i]) + "ms",
-			}
+		latencyDistribution[i] = LatencyDistribution{Percentage: pcs[i],
+			Latency:       int(values[i]),
+			PercentageStr: fmt.Sprintf("%.1f", pcs[i]) + "%",
+			LatencyMs:     strconv.FormatFloat(float64(values[i])/1000, 'f', -1, 64) + "ms",
 		}
 	}
 	return latencyDistribution
 }
 
+// calculateNamedDurationMapDistribution calculates the count and latency distribution of every name
+func calculateNamedDurationMapDistribution(namedDurationMap map[string]histogram.Histogram) (map[string]int64, map[string][]LatencyDistribution) {
+	if len(namedDurationMap) == 0 {
+		return nil, nil
+	}
+	counts := make(map[string]int64, len(namedDurationMap))
+	distributions := make(map[string][]LatencyDistribution, len(namedDurationMap))
+	for name, durationMap := range namedDurationMap {
+		counts[name] = durationMap.TotalCount()
+		distributions[name] = calculateLatencyDistribution(durationMap)
+	}
+	return counts, distributions
+}
+
+// calculateSizeDistribution calculate size distribution of a histogram of bytes
+func calculateSizeDistribution(h histogram.Histogram) []SizeDistribution {
+	if h.TotalCount() == 0 {
+		return nil
+	}
+	pcs := []float64{10, 25, 50, 75, 90, 95, 99, 99.9}
+	values := h.ValuesAtPercentiles(pcs)
+	sizeDistribution := make([]SizeDistribution, len(pcs))
+	for i := 0; i < len(pcs); i++ {
+		sizeDistribution[i] = SizeDistribution{Percentage: pcs[i],
+			Size:          values[i],
+			PercentageStr: fmt.Sprintf("%.1f", pcs[i]) + "%",
+		}
+	}
+	return sizeDistribution
+}
+
+// reuseRate returns the rate of the reused connections in the connections got by the requests
+func reuseRate(newNum, reusedNum int64) float64 {
+	if newNum+reusedNum == 0 {
+		return 0
+	}
+	return formatDecimal(float64(reusedNum) / float64(newNum+reusedNum))
+}
+
+// throughput returns the megabytes per second of the bytes in the seconds
+func throughput(bytes int64, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return formatDecimal(float64(bytes) / 1e6 / seconds)
+}
+
 func formatDecimal(value float64) float64 {
 	value, _ = strconv.ParseFloat(fmt.Sprintf("%.2f", value), 64)
 	return value
@@ -1,6 +1,9 @@
 package biz
 
-import "context"
+import (
+	"context"
+	"github.com/peckfly/gopeck/pkg/histogram"
+)
 
 type (
 	Report struct {
@@ -9,13 +12,49 @@ type (
 		Url       string `json:"url"`
 		Timestamp int64  `json:"timestamp"`
 
-		TotalNum                   int64            `json:"total_num"`
-		TotalResponseContentLength int64            `json:"total_response_content_length"`
-		DurationMap                map[int32]int64  `json:"duration_map"`
-		StatusMap                  map[int32]int64  `json:"status_map"`
-		ErrorMap                   map[string]int64 `json:"error_map"`
-		BodyCheckResultMap         map[string]int64 `json:"body_check_result_map"`
-		LatencyMap                 map[string]int32 `json:"latency_map"`
+		TotalNum                   int64                          `json:"total_num"`
+		TotalResponseContentLength int64                          `json:"total_response_content_length"`
+		DurationMap                histogram.Histogram            `json:"duration_map"`
+		StatusMap                  map[int32]int64                `json:"status_map"`
+		ErrorMap                   map[string]int64               `json:"error_map"`
+		BodyCheckResultMap         map[string]int64               `json:"body_check_result_map"`
+		LatencyMap                 map[string]int64               `json:"latency_map"`
+		HandshakeDurationMap       histogram.Histogram            `json:"handshake_duration_map"`
+		MessageDurationMap         histogram.Histogram            `json:"message_duration_map"`
+		DisconnectNum              int64                          `json:"disconnect_num"`
+		FirstByteDurationMap       histogram.Histogram            `json:"first_byte_duration_map"`
+		FirstEventDurationMap      histogram.Histogram            `json:"first_event_duration_map"`
+		EventGapDurationMap        histogram.Histogram            `json:"event_gap_duration_map"`
+		StreamDurationMap          histogram.Histogram            `json:"stream_duration_map"`
+		ConnectDurationMap         histogram.Histogram            `json:"connect_duration_map"`
+		WriteDurationMap           histogram.Histogram            `json:"write_duration_map"`
+		ReadDurationMap            histogram.Histogram            `json:"read_duration_map"`
+		DnsDurationMap             histogram.Histogram            `json:"dns_duration_map"`
+		TlsDurationMap             histogram.Histogram            `json:"tls_duration_map"`
+		WaitDurationMap            histogram.Histogram            `json:"wait_duration_map"`
+		TransferDurationMap        histogram.Histogram            `json:"transfer_duration_map"`
+		CommandDurationMap         map[string]histogram.Histogram `json:"command_duration_map"`
+		StepDurationMap            map[string]histogram.Histogram `json:"step_duration_map"`
+		StepErrorMap               map[string]int64               `json:"step_error_map"`
+		ResponseDurationMap        histogram.Histogram            `json:"response_duration_map"`
+		LateNum                    int64                          `json:"late_num"`
+		SkippedNum                 int64                          `json:"skipped_num"`
+		TotalRequestBytes          int64                          `json:"total_request_bytes"`
+		TotalResponseBytes         int64                          `json:"total_response_bytes"`
+		UploadThroughput           float64                        `json:"upload_throughput"`
+		DownloadThroughput         float64                        `json:"download_throughput"`
+		ResponseSizeMap            histogram.Histogram            `json:"response_size_map"`
+		TlsHandshakeNum            int64                          `json:"tls_handshake_num"`
+		TlsResumedNum              int64                          `json:"tls_resumed_num"`
+		TlsVersionMap              map[string]int64               `json:"tls_version_map"`
+		TlsCipherMap               map[string]int64               `json:"tls_cipher_map"`
+		NewConnNum                 int64                          `json:"new_conn_num"`
+		ReusedConnNum              int64                          `json:"reused_conn_num"`
+		ConnReuseRate              float64                        `json:"conn_reuse_rate"`
+		PeakOpenConnNum            int64                          `json:"peak_open_conn_num"`
+		NewConnMap                 map[string]int64               `json:"new_conn_map"`
+		ReusedConnMap              map[string]int64               `json:"reused_conn_map"`
+		PeakOpenConnMap            map[string]int64               `json:"peak_open_conn_map"`
 	}
 
 	ReporterRepository interface {
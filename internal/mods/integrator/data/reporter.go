@@ -6,12 +6,13 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/peckfly/gopeck/internal/conf"
 	"github.com/peckfly/gopeck/internal/mods/integrator/biz"
+	"github.com/peckfly/gopeck/pkg/histogram"
 	"github.com/peckfly/gopeck/pkg/log/logc"
 	"go.uber.org/zap"
 )
 
 // refer to internal/mods/integrator/biz/repo.go
-const reportColumns = `plan_id,task_id,url,timestamp,total_num,total_response_content_length,duration_map,status_map,error_map,body_check_result_map,latency_map`
+const reportColumns = `plan_id,task_id,url,timestamp,total_num,total_response_content_length,duration_map,status_map,error_map,body_check_result_map,latency_map,handshake_duration_map,message_duration_map,disconnect_num,first_byte_duration_map,first_event_duration_map,event_gap_duration_map,stream_duration_map,connect_duration_map,write_duration_map,read_duration_map,dns_duration_map,tls_duration_map,wait_duration_map,transfer_duration_map,command_duration_map,step_duration_map,step_error_map,response_duration_map,late_num,skipped_num,total_request_bytes,total_response_bytes,upload_throughput,download_throughput,response_size_map,tls_handshake_num,tls_resumed_num,tls_version_map,tls_cipher_map,new_conn_num,reused_conn_num,conn_reuse_rate,peak_open_conn_num,new_conn_map,reused_conn_map,peak_open_conn_map`
 
 type reporterRepository struct {
 	client    driver.Conn
@@ -43,11 +44,47 @@ func (r *reporterRepository) Report(ctx context.Context, details []*biz.Report)
 			row.Timestamp,
 			row.TotalNum,
 			row.TotalResponseContentLength,
-			row.DurationMap,
+			map[int64]int64(row.DurationMap),
 			row.StatusMap,
 			row.ErrorMap,
 			row.BodyCheckResultMap,
 			row.LatencyMap,
+			map[int64]int64(row.HandshakeDurationMap),
+			map[int64]int64(row.MessageDurationMap),
+			row.DisconnectNum,
+			map[int64]int64(row.FirstByteDurationMap),
+			map[int64]int64(row.FirstEventDurationMap),
+			map[int64]int64(row.EventGapDurationMap),
+			map[int64]int64(row.StreamDurationMap),
+			map[int64]int64(row.ConnectDurationMap),
+			map[int64]int64(row.WriteDurationMap),
+			map[int64]int64(row.ReadDurationMap),
+			map[int64]int64(row.DnsDurationMap),
+			map[int64]int64(row.TlsDurationMap),
+			map[int64]int64(row.WaitDurationMap),
+			map[int64]int64(row.TransferDurationMap),
+			namedHistogramMap(row.CommandDurationMap),
+			namedHistogramMap(row.StepDurationMap),
+			row.StepErrorMap,
+			map[int64]int64(row.ResponseDurationMap),
+			row.LateNum,
+			row.SkippedNum,
+			row.TotalRequestBytes,
+			row.TotalResponseBytes,
+			row.UploadThroughput,
+			row.DownloadThroughput,
+			map[int64]int64(row.ResponseSizeMap),
+			row.TlsHandshakeNum,
+			row.TlsResumedNum,
+			row.TlsVersionMap,
+			row.TlsCipherMap,
+			row.NewConnNum,
+			row.ReusedConnNum,
+			row.ConnReuseRate,
+			row.PeakOpenConnNum,
+			row.NewConnMap,
+			row.ReusedConnMap,
+			row.PeakOpenConnMap,
 		); err != nil {
 			logc.Error(ctx, "failed to append data: ", zap.Error(err))
 		}
@@ -57,3 +94,12 @@ func (r *reporterRepository) Report(ctx context.Context, details []*biz.Report)
 	}
 	return err
 }
+
+// namedHistogramMap converts the named histograms to the plain map type of the clickhouse column
+func namedHistogramMap(m map[string]histogram.Histogram) map[string]map[int64]int64 {
+	plain := make(map[string]map[int64]int64, len(m))
+	for name, h := range m {
+		plain[name] = h
+	}
+	return plain
+}
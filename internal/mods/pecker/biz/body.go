@@ -0,0 +1,96 @@
+package biz
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"io"
+	"sync"
+)
+
+var errBodyChecksumMismatch = errors.New("response body checksum mismatch")
+
+// bodyBufferPool holds the buffers of hashing the response bodies and reading the chunked streams, draining
+// uses the pool of io.Discard
+var bodyBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, streamReadSize)
+		return &buf
+	},
+}
+
+// bodyReader reads the http response bodies by the body mode of the task. The body is buffered only
+// if the mode is buffer or the response check script needs it, and the response size is the bytes read.
+type bodyReader struct {
+	mode     enums.BodyMode
+	checksum []byte
+	keep     bool
+}
+
+func newBodyReader(r *Requester) (*bodyReader, error) {
+	b := &bodyReader{
+		mode: enums.BodyMode(r.BodyMode),
+		keep: enums.BodyMode(r.BodyMode) == enums.BufferBody || len(r.ResponseCheckScript) > 0,
+	}
+	if b.mode == 0 || b.mode == enums.DiscardBody {
+		// draining the body counts its size at no cost, so discard is the same as size only
+		b.mode = enums.SizeOnlyBody
+	}
+	if b.mode == enums.HashBody {
+		checksum, err := hex.DecodeString(r.BodyChecksum)
+		if err != nil || len(checksum) != sha256.Size {
+			return nil, fmt.Errorf("invalid sha256 body checksum: %s", r.BodyChecksum)
+		}
+		b.checksum = checksum
+	}
+	return b, nil
+}
+
+// read reads the body until it ends, it returns the bytes read and the body if it is kept
+func (b *bodyReader) read(body io.Reader) (int64, []byte, error) {
+	if b.keep {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return int64(len(data)), data, err
+		}
+		return b.readBytes(data)
+	}
+	switch b.mode {
+	case enums.HashBody:
+		bufp := bodyBufferPool.Get().(*[]byte)
+		defer bodyBufferPool.Put(bufp)
+		h := sha256.New()
+		size, err := io.CopyBuffer(h, onlyReader{body}, *bufp)
+		if err == nil && !bytes.Equal(h.Sum((*bufp)[:0]), b.checksum) {
+			err = errBodyChecksumMismatch
+		}
+		return size, nil, err
+	default:
+		size, err := io.Copy(io.Discard, body)
+		return size, nil, err
+	}
+}
+
+// readBytes handles the body read in memory already
+func (b *bodyReader) readBytes(data []byte) (int64, []byte, error) {
+	var err error
+	size := int64(len(data))
+	if b.mode == enums.HashBody {
+		sum := sha256.Sum256(data)
+		if !bytes.Equal(sum[:], b.checksum) {
+			err = errBodyChecksumMismatch
+		}
+	}
+	if !b.keep {
+		data = nil
+	}
+	return size, data, err
+}
+
+// onlyReader hides the WriterTo of the body, so that the copy uses the pooled buffer
+type onlyReader struct {
+	io.Reader
+}
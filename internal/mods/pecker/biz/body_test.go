@@ -0,0 +1,73 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyReader(t *testing.T) {
+	body := strings.Repeat("gopeck", 10000)
+	sum := sha256.Sum256([]byte(body))
+	checksum := hex.EncodeToString(sum[:])
+
+	cases := []struct {
+		r    *Requester
+		size int64
+		kept bool
+		err  error
+	}{
+		{r: &Requester{}, size: int64(len(body))},
+		{r: &Requester{BodyMode: int32(enums.DiscardBody)}, size: int64(len(body))},
+		{r: &Requester{BodyMode: int32(enums.SizeOnlyBody)}, size: int64(len(body))},
+		{r: &Requester{BodyMode: int32(enums.BufferBody)}, size: int64(len(body)), kept: true},
+		{r: &Requester{ResponseCheckScript: "script"}, size: int64(len(body)), kept: true},
+		{r: &Requester{BodyMode: int32(enums.HashBody), BodyChecksum: checksum}, size: int64(len(body))},
+		{r: &Requester{BodyMode: int32(enums.HashBody), BodyChecksum: checksum, ResponseCheckScript: "script"}, size: int64(len(body)), kept: true},
+		{r: &Requester{BodyMode: int32(enums.HashBody), BodyChecksum: strings.Repeat("0", 64)}, size: int64(len(body)), err: errBodyChecksumMismatch},
+	}
+	for i, c := range cases {
+		b, err := newBodyReader(c.r)
+		assert.NoError(t, err, i)
+		size, data, err := b.read(strings.NewReader(body))
+		assert.Equal(t, c.err, err, i)
+		assert.Equal(t, c.size, size, i)
+		assert.Equal(t, c.kept, data != nil, i)
+		size, data, err = b.readBytes([]byte(body))
+		assert.Equal(t, c.err, err, i)
+		assert.Equal(t, c.size, size, i)
+		assert.Equal(t, c.kept, data != nil, i)
+	}
+
+	_, err := newBodyReader(&Requester{BodyMode: int32(enums.HashBody), BodyChecksum: "gopeck"})
+	assert.Error(t, err)
+}
+
+func TestBodyModeChunked(t *testing.T) {
+	body := strings.Repeat("gopeck", 10000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// flushing makes the response chunked without content length
+		for i := 0; i < 10; i++ {
+			_, _ = w.Write([]byte(body[i*len(body)/10 : (i+1)*len(body)/10]))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+	for _, fastHttp := range []bool{false, true} {
+		r := &Requester{Method: http.MethodGet, Url: srv.URL, Timeout: 3, FastHttp: fastHttp}
+		e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+		assert.NoError(t, err)
+		result := e.Execute(context.Background())
+		e.Teardown(context.Background())
+		assert.Empty(t, result.Err)
+		assert.Equal(t, int64(len(body)), result.ResponseContentLength, fastHttp)
+	}
+}
@@ -0,0 +1,97 @@
+package biz
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// countingConn counts the bytes read and written on the connection, the counts are updated by the
+// goroutines of the transport and loaded by the request using the connection
+type countingConn struct {
+	net.Conn
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// connCounter returns the counting connection under the connection, nil if it is not counted
+func connCounter(conn net.Conn) *countingConn {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	counter, _ := conn.(*countingConn)
+	return counter
+}
+
+// connGauge counts the open connections of an executor, a connection is counted from the dial until it is closed
+type connGauge struct {
+	open atomic.Int64
+}
+
+// track counts the connection as open until it is closed
+func (g *connGauge) track(conn net.Conn) net.Conn {
+	g.open.Add(1)
+	return &gaugedConn{Conn: conn, gauge: g}
+}
+
+// gaugedConn is the connection counted by the gauge, closing it more than once is counted once
+type gaugedConn struct {
+	net.Conn
+	gauge  *connGauge
+	closed atomic.Bool
+}
+
+func (c *gaugedConn) Close() error {
+	if c.closed.CompareAndSwap(false, true) {
+		c.gauge.open.Add(-1)
+	}
+	return c.Conn.Close()
+}
+
+// countingReader counts the bytes read from the reader
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	r.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written to it and drops them
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.n += int64(len(b))
+	return len(b), nil
+}
+
+// responseHeaderSize returns the bytes of the status line and the headers of the response in http/1.1
+func responseHeaderSize(resp *http.Response) int64 {
+	// the status line is like "HTTP/1.1 200 OK\r\n" and the headers end with "\r\n"
+	size := int64(len(resp.Proto)+len(resp.Status)+3) + 2
+	for key, values := range resp.Header {
+		for _, value := range values {
+			size += int64(len(key) + len(value) + 4)
+		}
+	}
+	return size
+}
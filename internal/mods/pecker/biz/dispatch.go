@@ -0,0 +1,91 @@
+package biz
+
+import (
+	"context"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"sync"
+	"time"
+)
+
+// dispatcher hands the requests of rps mode over to the workers, a new worker is started when all
+// the workers are busy. The in-flight requests are limited by the slots if the task has max in-flight,
+// a slot is taken before the request is handed over and released by the worker after the request.
+// It is used by the generator goroutine only.
+type dispatcher struct {
+	taskChan     chan time.Time
+	slots        chan struct{}
+	wg           sync.WaitGroup
+	workers      int
+	policy       enums.InFlightPolicy
+	queueTimeout time.Duration
+	spawn        func()
+	// skipped collects the requests skipped by the generator
+	skipped *collector
+}
+
+func (b *RequesterUsecase) newDispatcher(ctx context.Context, r *Requester) *dispatcher {
+	d := &dispatcher{
+		taskChan:     make(chan time.Time),
+		policy:       enums.InFlightPolicy(r.InFlightPolicy),
+		queueTimeout: time.Duration(r.InFlightQueueTimeout) * time.Millisecond,
+		skipped:      b.newCollector(r),
+	}
+	if r.MaxInFlight > 0 {
+		d.slots = make(chan struct{}, r.MaxInFlight)
+	}
+	d.spawn = func() {
+		d.wg.Add(1)
+		d.workers++
+		go b.requestFromChan(ctx, d.taskChan, d.slots, &d.wg, r)
+	}
+	d.spawn()
+	return d
+}
+
+// dispatch hands the request of the intended send time over to a worker. When the max in-flight
+// requests are reached, the request is skipped, or queued until a slot is released by the queue policy
+// and skipped after the queue timeout. It returns false if the request is skipped.
+func (d *dispatcher) dispatch(intended time.Time) bool {
+	if d.slots != nil && !d.acquire() {
+		d.skipped.skip(time.Now().Unix())
+		return false
+	}
+	select {
+	case d.taskChan <- intended:
+		return true
+	default:
+	}
+	// with a slot taken, a worker not in flight is going to receive soon if the workers are enough
+	if d.slots == nil || d.workers < cap(d.slots) {
+		d.spawn()
+	}
+	d.taskChan <- intended
+	return true
+}
+
+// acquire takes an in-flight slot by the policy
+func (d *dispatcher) acquire() bool {
+	select {
+	case d.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if d.policy != enums.QueueInFlight {
+		return false
+	}
+	timer := time.NewTimer(d.queueTimeout)
+	defer timer.Stop()
+	select {
+	case d.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// close waits for the workers to finish the requests in flight
+func (d *dispatcher) close() {
+	close(d.taskChan)
+	d.wg.Wait()
+	d.skipped.close()
+}
@@ -0,0 +1,97 @@
+package biz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/atomicx"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockExecutor is a protocol executor blocking every request until released
+type blockExecutor struct {
+	release chan struct{}
+}
+
+func (e blockExecutor) Setup(ctx context.Context, r *Requester) error { return nil }
+
+func (e blockExecutor) Execute(ctx context.Context) *repo.Result {
+	<-e.release
+	return &repo.Result{TimeStamp: time.Now().Unix(), Duration: time.Millisecond}
+}
+
+func (e blockExecutor) Teardown(ctx context.Context) {}
+
+// collectTotal sums the aggregates handed over to stat
+func collectTotal(r *Requester) (total, skipped int64) {
+	close(r.aggregates)
+	for ar := range r.aggregates {
+		total += ar.TotalNum
+		skipped += ar.SkippedNum
+	}
+	return total, skipped
+}
+
+func TestDispatcherSkip(t *testing.T) {
+	stops[16] = atomicx.ForAtomicBool(false)
+	defer delete(stops, 16)
+	e := blockExecutor{release: make(chan struct{})}
+	r := &Requester{TaskId: 16, executor: e, MaxInFlight: 2, InFlightPolicy: int32(enums.SkipInFlight), aggregates: make(chan *repo.Aggregate, 10)}
+	d := (&RequesterUsecase{}).newDispatcher(context.Background(), r)
+	assert.True(t, d.dispatch(time.Now()))
+	assert.True(t, d.dispatch(time.Now()))
+	assert.False(t, d.dispatch(time.Now()))
+	assert.LessOrEqual(t, d.workers, 2)
+	close(e.release)
+	d.close()
+	total, skipped := collectTotal(r)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, int64(1), skipped)
+}
+
+func TestDispatcherQueue(t *testing.T) {
+	stops[16] = atomicx.ForAtomicBool(false)
+	defer delete(stops, 16)
+	e := blockExecutor{release: make(chan struct{})}
+	r := &Requester{TaskId: 16, executor: e, MaxInFlight: 1, InFlightPolicy: int32(enums.QueueInFlight), InFlightQueueTimeout: 20, aggregates: make(chan *repo.Aggregate, 10)}
+	d := (&RequesterUsecase{}).newDispatcher(context.Background(), r)
+	assert.True(t, d.dispatch(time.Now()))
+
+	// the worker is busy until the queue timeout
+	start := time.Now()
+	assert.False(t, d.dispatch(time.Now()))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// the worker gets idle while queueing
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		e.release <- struct{}{}
+	}()
+	assert.True(t, d.dispatch(time.Now()))
+	assert.LessOrEqual(t, d.workers, 1)
+	close(e.release)
+	d.close()
+	total, skipped := collectTotal(r)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, int64(1), skipped)
+}
+
+func TestDispatcherUnlimited(t *testing.T) {
+	stops[16] = atomicx.ForAtomicBool(false)
+	defer delete(stops, 16)
+	e := blockExecutor{release: make(chan struct{})}
+	r := &Requester{TaskId: 16, executor: e, aggregates: make(chan *repo.Aggregate, 10)}
+	d := (&RequesterUsecase{}).newDispatcher(context.Background(), r)
+	for i := 0; i < 5; i++ {
+		assert.True(t, d.dispatch(time.Now()))
+	}
+	assert.GreaterOrEqual(t, d.workers, 5)
+	close(e.release)
+	d.close()
+	total, skipped := collectTotal(r)
+	assert.Equal(t, int64(5), total)
+	assert.Zero(t, skipped)
+}
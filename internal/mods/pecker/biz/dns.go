@@ -0,0 +1,185 @@
+package biz
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/consts"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"golang.org/x/net/dns/dnsmessage"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+// dnsUdpSize is the max size of a dns response over udp
+const dnsUdpSize = 4096
+
+var (
+	errDnsTruncated  = errors.New("truncated")
+	errDnsIdMismatch = errors.New("response id mismatch")
+)
+
+// dnsExecutor sends one dns query per execution to the server, over udp or over tcp with length prefix
+type dnsExecutor struct {
+	conf    *conf.WorkerStressConf
+	r       *Requester
+	network string
+	address string
+	qtype   dnsmessage.Type
+	names   []dnsmessage.Name
+	dial    netx.DialFunc
+}
+
+func newDnsExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &dnsExecutor{conf: conf}
+}
+
+func (e *dnsExecutor) Setup(ctx context.Context, r *Requester) error {
+	e.r = r
+	switch {
+	case strings.HasPrefix(r.Url, consts.TcpScheme):
+		e.network = "tcp"
+		e.address = netx.DnsServerAddress(strings.TrimPrefix(r.Url, consts.TcpScheme))
+	case strings.HasPrefix(r.Url, consts.UdpScheme):
+		e.network = "udp"
+		e.address = netx.DnsServerAddress(strings.TrimPrefix(r.Url, consts.UdpScheme))
+	default:
+		return fmt.Errorf("dns server should start with udp:// or tcp://: %s", r.Url)
+	}
+	qtype, ok := netx.ParseDnsQueryType(r.DnsQueryType)
+	if !ok {
+		return fmt.Errorf("not support dns query type: %s", r.DnsQueryType)
+	}
+	e.qtype = qtype
+	dial, _, err := newDialer(e.conf, r, time.Duration(r.Timeout)*time.Second)
+	if err != nil {
+		return err
+	}
+	e.dial = dial
+	queryNames := []string{r.Body}
+	if len(r.DynamicParams) > 0 {
+		queryNames = queryNames[:0]
+		for _, param := range r.DynamicParams {
+			queryNames = append(queryNames, param.Body)
+		}
+	}
+	for _, queryName := range queryNames {
+		name, err := netx.ParseDnsName(queryName)
+		if err != nil {
+			return fmt.Errorf("invalid dns query name %s: %w", queryName, err)
+		}
+		e.names = append(e.names, name)
+	}
+	return nil
+}
+
+func (e *dnsExecutor) Execute(ctx context.Context) *repo.Result {
+	name := e.names[0]
+	if len(e.names) > 1 {
+		name = e.names[rand.Intn(len(e.names))]
+	}
+	id := uint16(rand.Uint32())
+	result := &repo.Result{TimeStamp: time.Now().Unix()}
+	query, err := e.buildQuery(id, name)
+	if err != nil {
+		result.Err = cutError(err, e.conf.ErrorCutLength)
+		result.StatusCode = noResponseStatus
+		return result
+	}
+	timeout := time.Duration(e.r.Timeout) * time.Second
+	s := now()
+	header, size, op, err := e.exchange(ctx, id, query, timeout)
+	result.Duration = now() - s
+	result.ResponseContentLength = int64(size)
+	result.StatusCode = int(header.RCode)
+	if err != nil {
+		result.Err = socketErrorClass(op, err, e.conf.ErrorCutLength)
+		if !errors.Is(err, errDnsTruncated) {
+			// the truncated response is answered by the server, its rcode is kept
+			result.StatusCode = noResponseStatus
+		}
+	}
+	return result
+}
+
+func (e *dnsExecutor) Teardown(ctx context.Context) {
+}
+
+// buildQuery builds the recursive query message of the name
+func (e *dnsExecutor) buildQuery(id uint16, name dnsmessage.Name) ([]byte, error) {
+	b := dnsmessage.NewBuilder(make([]byte, 2, 512), dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: e.qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		return nil, err
+	}
+	// the first two bytes are reserved for the length prefix of tcp
+	binary.BigEndian.PutUint16(msg, uint16(len(msg)-2))
+	if e.network == "udp" {
+		return msg[2:], nil
+	}
+	return msg, nil
+}
+
+// exchange sends the query and reads the response header, op is the failed operation if there is an error
+func (e *dnsExecutor) exchange(ctx context.Context, id uint16, query []byte, timeout time.Duration) (dnsmessage.Header, int, string, error) {
+	var header dnsmessage.Header
+	conn, err := e.dial(ctx, e.network, e.address)
+	if err != nil {
+		return header, 0, "dial", err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err = conn.Write(query); err != nil {
+		return header, 0, "write", err
+	}
+	for {
+		response, err := e.readResponse(conn)
+		if err != nil {
+			return header, len(response), "read", err
+		}
+		var p dnsmessage.Parser
+		if header, err = p.Start(response); err != nil {
+			return header, len(response), "parse", err
+		}
+		if header.ID != id {
+			if e.network == "udp" {
+				// a late response of another query, keep waiting for the response of this query
+				continue
+			}
+			return header, len(response), "read", errDnsIdMismatch
+		}
+		if header.Truncated {
+			return header, len(response), "read", errDnsTruncated
+		}
+		return header, len(response), "", nil
+	}
+}
+
+// readResponse reads one dns message, a udp message is one datagram and a tcp message is length prefixed
+func (e *dnsExecutor) readResponse(conn net.Conn) ([]byte, error) {
+	if e.network == "udp" {
+		buf := make([]byte, dnsUdpSize)
+		n, err := conn.Read(buf)
+		return buf[:n], err
+	}
+	var prefix [2]byte
+	if _, err := io.ReadFull(conn, prefix[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(prefix[:]))
+	n, err := io.ReadFull(conn, buf)
+	return buf[:n], err
+}
@@ -0,0 +1,175 @@
+package biz
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// resolverStandIn answers the queries like a resolver: nxdomain.test. is not found, truncated.test. is
+// truncated with servfail over udp, ignored.test. is never answered and the other names resolve to 127.0.0.1
+func resolverStandIn(t *testing.T, query []byte, udp bool) []byte {
+	var p dnsmessage.Parser
+	header, err := p.Start(query)
+	if err != nil {
+		t.Errorf("parse query error: %v", err)
+		return nil
+	}
+	question, err := p.Question()
+	if err != nil {
+		t.Errorf("parse question error: %v", err)
+		return nil
+	}
+	response := dnsmessage.Header{ID: header.ID, Response: true, RecursionDesired: header.RecursionDesired, RecursionAvailable: true}
+	switch question.Name.String() {
+	case "ignored.test.":
+		return nil
+	case "nxdomain.test.":
+		response.RCode = dnsmessage.RCodeNameError
+	case "truncated.test.":
+		response.Truncated = udp
+		if udp {
+			response.RCode = dnsmessage.RCodeServerFailure
+		}
+	}
+	b := dnsmessage.NewBuilder(nil, response)
+	_ = b.StartQuestions()
+	_ = b.Question(question)
+	_ = b.StartAnswers()
+	if response.RCode == dnsmessage.RCodeSuccess && question.Type == dnsmessage.TypeA {
+		_ = b.AResource(dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60},
+			dnsmessage.AResource{A: [4]byte{127, 0, 0, 1}})
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		t.Errorf("build response error: %v", err)
+	}
+	return msg
+}
+
+func startResolverStandIn(t *testing.T) (udpAddr string, tcpAddr string) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = pc.Close()
+		_ = ln.Close()
+	})
+	go func() {
+		buf := make([]byte, dnsUdpSize)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := resolverStandIn(t, buf[:n], true); response != nil {
+				_, _ = pc.WriteTo(response, addr)
+			}
+		}
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var prefix [2]byte
+				if _, err := io.ReadFull(conn, prefix[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(prefix[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				response := resolverStandIn(t, query, false)
+				if response == nil {
+					_, _ = io.Copy(io.Discard, conn)
+					return
+				}
+				binary.BigEndian.PutUint16(prefix[:], uint16(len(response)))
+				_, _ = conn.Write(append(prefix[:], response...))
+			}()
+		}
+	}()
+	return pc.LocalAddr().String(), ln.Addr().String()
+}
+
+func TestDnsExecutor(t *testing.T) {
+	udpAddr, tcpAddr := startResolverStandIn(t)
+	tests := []struct {
+		name       string
+		url        string
+		queryType  string
+		queryName  string
+		statusCode int
+		err        string
+	}{
+		{name: "udp a", url: "udp://" + udpAddr, queryName: "www.example.test", statusCode: int(dnsmessage.RCodeSuccess)},
+		{name: "udp aaaa", url: "udp://" + udpAddr, queryType: "AAAA", queryName: "www.example.test.", statusCode: int(dnsmessage.RCodeSuccess)},
+		{name: "tcp srv", url: "tcp://" + tcpAddr, queryType: "SRV", queryName: "_http._tcp.example.test", statusCode: int(dnsmessage.RCodeSuccess)},
+		{name: "nxdomain", url: "udp://" + udpAddr, queryType: "TXT", queryName: "nxdomain.test", statusCode: int(dnsmessage.RCodeNameError)},
+		{name: "truncated over udp", url: "udp://" + udpAddr, queryName: "truncated.test", statusCode: int(dnsmessage.RCodeServerFailure), err: "read: truncated"},
+		{name: "not truncated over tcp", url: "tcp://" + tcpAddr, queryName: "truncated.test", statusCode: int(dnsmessage.RCodeSuccess)},
+		{name: "udp timeout", url: "udp://" + udpAddr, queryName: "ignored.test", statusCode: noResponseStatus, err: "read: timeout"},
+		{name: "tcp timeout", url: "tcp://" + tcpAddr, queryName: "ignored.test", statusCode: noResponseStatus, err: "read: timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Requester{ProtocolType: 6, Url: tt.url, DnsQueryType: tt.queryType, Body: tt.queryName, Timeout: 1}
+			e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+			assert.NoError(t, err)
+			result := e.Execute(context.Background())
+			assert.Equal(t, tt.err, result.Err)
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+			if tt.statusCode != noResponseStatus {
+				assert.Greater(t, result.ResponseContentLength, int64(0))
+			}
+		})
+	}
+}
+
+func TestDnsExecutorDynamicNames(t *testing.T) {
+	udpAddr, _ := startResolverStandIn(t)
+	r := &Requester{
+		ProtocolType: 6,
+		Url:          "udp://" + udpAddr,
+		Timeout:      1,
+		DynamicParams: []*DynamicParam{
+			{Body: "a.example.test"},
+			{Body: "nxdomain.test"},
+		},
+	}
+	e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+	assert.NoError(t, err)
+	statusCodes := make(map[int]int)
+	for i := 0; i < 50; i++ {
+		result := e.Execute(context.Background())
+		assert.Empty(t, result.Err)
+		statusCodes[result.StatusCode]++
+	}
+	assert.Len(t, statusCodes, 2)
+}
+
+func TestDnsExecutorSetup(t *testing.T) {
+	cfg := &conf.WorkerStressConf{}
+	_, err := newProtocolExecutor(context.Background(), cfg, &Requester{ProtocolType: 6, Url: "http://127.0.0.1", Body: "a.test"})
+	assert.Error(t, err)
+	_, err = newProtocolExecutor(context.Background(), cfg, &Requester{ProtocolType: 6, Url: "udp://127.0.0.1", DnsQueryType: "MX", Body: "a.test"})
+	assert.Error(t, err)
+	e, err := newProtocolExecutor(context.Background(), cfg, &Requester{ProtocolType: 6, Url: "udp://127.0.0.1", Body: "a.test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:53", e.(*dnsExecutor).address)
+}
@@ -0,0 +1,79 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+)
+
+type (
+	// ProtocolExecutor sends the requests of one protocol, the pacing, stop and reporting
+	// logic of the requester is shared by all protocols.
+	ProtocolExecutor interface {
+		// Setup prepares the executor before the stress starts, e.g. building clients
+		Setup(ctx context.Context, r *Requester) error
+		// Execute sends one request and returns its result, nil means nothing should be reported
+		Execute(ctx context.Context) *repo.Result
+		// Teardown releases the resources held by the executor after the stress is done
+		Teardown(ctx context.Context)
+	}
+
+	executorBuilder func(conf *conf.WorkerStressConf) ProtocolExecutor
+)
+
+// noResponseStatus is the status code of the requests failed without a response, it is not 0, which is
+// the status of the success of some protocols, e.g. the OK code of grpc and the NOERROR rcode of dns
+const noResponseStatus = -1
+
+// executorBuilders holds the executor builder of every supported protocol type
+var executorBuilders = map[enums.ProtocolType]executorBuilder{
+	enums.Http:      newHttpExecutor,
+	enums.Grpc:      newGrpcExecutor,
+	enums.WebSocket: newWebsocketExecutor,
+	enums.Tcp:       newTcpExecutor,
+	enums.Udp:       newUdpExecutor,
+	enums.Dns:       newDnsExecutor,
+	enums.Redis:     newRedisExecutor,
+	enums.Scenario:  newScenarioExecutor,
+}
+
+// newProtocolExecutor builds the executor selected by the protocol type of the requester and set it up
+func newProtocolExecutor(ctx context.Context, conf *conf.WorkerStressConf, r *Requester) (ProtocolExecutor, error) {
+	protocolType := enums.ProtocolType(r.ProtocolType)
+	if protocolType <= 0 {
+		protocolType = enums.Http
+	}
+	builder, ok := executorBuilders[protocolType]
+	if !ok {
+		return nil, fmt.Errorf("not support protocol type: %d", r.ProtocolType)
+	}
+	if protocolType == enums.Http && r.FastHttp {
+		// the http engine is selected by the task
+		builder = newFastHttpExecutor
+	}
+	executor := builder(conf)
+	if err := executor.Setup(ctx, r); err != nil {
+		return nil, err
+	}
+	return executor, nil
+}
+
+// cutError returns the tail of the error message limited by cutLength
+func cutError(err error, cutLength int) string {
+	if err == nil {
+		return ""
+	}
+	if isPortsExhausted(err) {
+		return "dial: " + portsExhausted
+	}
+	prefix, errorStr := "", err.Error()
+	if reason, ok := tlsVerifyError(err); ok {
+		prefix, errorStr = tlsVerifyErrorPrefix, reason
+	}
+	if len(errorStr) > cutLength {
+		errorStr = errorStr[len(errorStr)-cutLength:]
+	}
+	return prefix + errorStr
+}
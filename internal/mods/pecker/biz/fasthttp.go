@@ -0,0 +1,242 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/log/logc"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpproxy"
+	"go.uber.org/zap"
+	"io"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+)
+
+// fastHttpMaxRedirects is the max redirects followed by the fasthttp engine, the same as net/http
+const fastHttpMaxRedirects = 10
+
+// fastHttpExecutor executes http/1.1 requests with a fasthttp client, the requests and the responses
+// are taken from the pools of fasthttp and the request prototypes are copied into them, so that a
+// request costs almost no allocation. The phases of the request and whether its connection is new or
+// reused are not traced by this engine, while the open connections are counted.
+type fastHttpExecutor struct {
+	conf      *conf.WorkerStressConf
+	r         *Requester
+	client    *fasthttp.Client
+	templates []*fasthttp.Request
+	body      *bodyReader
+	conns     connGauge
+	timeout   time.Duration
+	// stream is true if the body is read from the stream, it is used by the stream types and the body size limit
+	stream bool
+}
+
+func newFastHttpExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &fastHttpExecutor{conf: conf}
+}
+
+func (e *fastHttpExecutor) Setup(ctx context.Context, r *Requester) error {
+	if r.H2 {
+		return errors.New("fasthttp engine does not support http2")
+	}
+	e.r = r
+	templates, err := newRequestTemplates(r)
+	if err != nil {
+		return err
+	}
+	for _, t := range templates {
+		e.templates = append(e.templates, newFastHttpRequest(t, r.DisableKeepAlive || r.TlsFreshHandshake))
+	}
+	e.body, err = newBodyReader(r)
+	if err != nil {
+		return err
+	}
+	if r.MaxConnections == 0 {
+		r.MaxConnections = int32(e.conf.DefaultMaxConnections)
+	}
+	tlsConfig, err := newTlsConfig(e.conf, r)
+	if err != nil {
+		return err
+	}
+	e.timeout = time.Duration(r.Timeout) * time.Second
+	e.stream = r.StreamType > 0 || r.MaxBodySize > 0
+	client := &fasthttp.Client{
+		TLSConfig:          tlsConfig,
+		MaxConnsPerHost:    int(r.MaxConnections),
+		ReadTimeout:        e.timeout,
+		WriteTimeout:       e.timeout,
+		StreamResponseBody: e.stream,
+		// the requests wait for a free connection like the transport of net/http
+		MaxConnWaitTimeout:       e.timeout,
+		NoDefaultUserAgentHeader: true,
+	}
+	if r.MaxBodySize > 0 {
+		// the larger bodies are streamed and cut by the limit instead of failing
+		client.MaxResponseBodySize = int(r.MaxBodySize)
+	}
+	dial, custom, err := newDialer(e.conf, r, e.timeout)
+	if err != nil {
+		return err
+	}
+	if custom {
+		// the dial of fasthttp caches the dns itself, it is replaced only if the task changes the dials
+		client.Dial = func(addr string) (net.Conn, error) {
+			return dial(context.Background(), "tcp", addr)
+		}
+	}
+	if len(r.Proxy) > 0 {
+		if proxyUrl, err := url.Parse(r.Proxy); err == nil {
+			if proxyUrl.Scheme == "socks5" {
+				client.Dial = fasthttpproxy.FasthttpSocksDialer(r.Proxy)
+			} else {
+				proxyAddr := proxyUrl.Host
+				if proxyUrl.User != nil {
+					proxyAddr = proxyUrl.User.String() + "@" + proxyAddr
+				}
+				client.Dial = fasthttpproxy.FasthttpHTTPDialerTimeout(proxyAddr, e.timeout)
+			}
+		}
+	}
+	// the connections are counted by the dial, the default one is the dial with the dns cache of fasthttp
+	connDial := client.Dial
+	if connDial == nil {
+		connDial = func(addr string) (net.Conn, error) {
+			return fasthttp.DialTimeout(addr, e.timeout)
+		}
+	}
+	client.Dial = func(addr string) (net.Conn, error) {
+		conn, err := connDial(addr)
+		if err != nil {
+			return nil, err
+		}
+		return e.conns.track(conn), nil
+	}
+	e.client = client
+	return nil
+}
+
+func (e *fastHttpExecutor) Execute(ctx context.Context) *repo.Result {
+	r := e.r
+	s := now()
+	template := e.templates[0]
+	if len(e.templates) > 1 {
+		template = e.templates[rand.Intn(len(e.templates))]
+	}
+	req, resp := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	template.CopyTo(req)
+	req.SetTimeout(e.timeout)
+	requestTime := time.Now().Unix()
+	var err error
+	if r.DisableRedirects {
+		err = e.client.Do(req, resp)
+	} else {
+		err = e.client.DoRedirects(req, resp, fastHttpMaxRedirects)
+	}
+	var errorStr string
+	code := noResponseStatus
+	var responseContentLength int64
+	var respBody []byte
+	var stream *streamResult
+	var resStart, resDuration time.Duration
+	// the bytes are counted by the request and the response, the chunk framing is not counted
+	requestBytes := int64(len(req.Header.Header()) + len(req.Body()))
+	var responseBytes int64
+	if err == nil {
+		resStart = now()
+		code = resp.StatusCode()
+		if e.stream {
+			counted := &countingReader{Reader: resp.BodyStream()}
+			body := io.Reader(counted)
+			if r.MaxBodySize > 0 {
+				body = io.LimitReader(body, r.MaxBodySize)
+			}
+			if r.StreamType > 0 {
+				stream, err = readStream(body, enums.ResponseStreamType(r.StreamType), r.responseChecker != nil)
+				respBody = stream.body
+				responseContentLength = stream.size
+			} else {
+				responseContentLength, respBody, err = e.body.read(body)
+			}
+			responseBytes = counted.n
+		} else {
+			data := resp.Body()
+			responseBytes = int64(len(data))
+			if e.body.keep {
+				data, err = resp.BodyUncompressed()
+			}
+			if err == nil {
+				responseContentLength, respBody, err = e.body.readBytes(data)
+			}
+		}
+		resDuration = now() - resStart
+		responseBytes += int64(len(resp.Header.Header()))
+		if err != nil {
+			errorStr = cutError(err, e.conf.ErrorCutLength)
+		}
+	} else {
+		errorStr = cutError(err, e.conf.ErrorCutLength)
+	}
+	var bodyResult string
+	if r.responseChecker != nil {
+		err = r.responseChecker.ExecuteScript(func(executor any) {
+			bodyResult = executor.(func(string) string)(string(respBody))
+		})
+		if err != nil {
+			logc.Error(ctx, "failed to execute script", zap.Error(err))
+		}
+	}
+	t := now()
+	result := &repo.Result{
+		Err:                   errorStr,
+		StatusCode:            code,
+		Duration:              t - s,
+		ResponseContentLength: responseContentLength,
+		TimeStamp:             requestTime,
+		BodyCheckResult:       bodyResult,
+		TransferDuration:      resDuration,
+		RequestBytes:          requestBytes,
+		ResponseBytes:         responseBytes,
+		OpenConns:             e.conns.open.Load(),
+	}
+	if stream != nil {
+		result.FirstByteDuration = resStart - s
+		if len(stream.events) > 0 {
+			result.FirstEventDuration = stream.events[0] - s
+			for i := 1; i < len(stream.events); i++ {
+				result.EventGaps = append(result.EventGaps, stream.events[i]-stream.events[i-1])
+			}
+		}
+		result.StreamDuration = stream.end - resStart
+	}
+	return result
+}
+
+func (e *fastHttpExecutor) Teardown(ctx context.Context) {
+	e.client.CloseIdleConnections()
+}
+
+// newFastHttpRequest builds the fasthttp request prototype of the template
+func newFastHttpRequest(t *requestTemplate, disableKeepAlive bool) *fasthttp.Request {
+	req := &fasthttp.Request{}
+	req.SetRequestURI(t.proto.URL.String())
+	req.Header.SetMethod(t.proto.Method)
+	for key, values := range t.proto.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if len(t.body) > 0 {
+		req.SetBody(t.body)
+	}
+	if disableKeepAlive {
+		req.SetConnectionClose()
+	}
+	return req
+}
@@ -0,0 +1,86 @@
+package biz
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFastHttpExecutor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/api/v1/users?"+r.URL.RawQuery, http.StatusFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.RawQuery + " " + r.Header.Get("X-Trace") + " " + string(body)))
+	}))
+	defer srv.Close()
+	newExecutor := func(r *Requester) ProtocolExecutor {
+		e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+		assert.NoError(t, err)
+		assert.IsType(t, &fastHttpExecutor{}, e)
+		return e
+	}
+	expect := `POST page=1&size=20&source=peck gopeck {"name":"gopeck","tags":["stress","http"]}`
+
+	r := *templateRequester
+	r.Url, r.Timeout, r.FastHttp = srv.URL+"/api/v1/users?source=peck", 3, true
+	e := newExecutor(&r)
+	defer e.Teardown(context.Background())
+	for i := 0; i < 2; i++ {
+		result := e.Execute(context.Background())
+		assert.Empty(t, result.Err)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.Equal(t, int64(len(expect)), result.ResponseContentLength)
+		assert.Positive(t, result.Duration)
+	}
+
+	// the redirects are followed unless they are disabled
+	r.Method, r.Body, r.Url = http.MethodGet, "", srv.URL+"/redirect?source=peck"
+	redirect := newExecutor(&r)
+	defer redirect.Teardown(context.Background())
+	assert.Equal(t, http.StatusOK, redirect.Execute(context.Background()).StatusCode)
+	r.DisableRedirects = true
+	noRedirect := newExecutor(&r)
+	defer noRedirect.Teardown(context.Background())
+	assert.Equal(t, http.StatusFound, noRedirect.Execute(context.Background()).StatusCode)
+
+	// the body larger than the limit is cut instead of failing
+	r.Url, r.DisableRedirects, r.MaxBodySize = srv.URL+"/api/v1/users", false, 4
+	limited := newExecutor(&r)
+	defer limited.Teardown(context.Background())
+	result := limited.Execute(context.Background())
+	assert.Empty(t, result.Err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	// http2 is not supported by the engine
+	r.H2 = true
+	_, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, &r)
+	assert.Error(t, err)
+}
+
+func BenchmarkFastHttpExecutor(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+	r := *templateRequester
+	r.ProtocolType, r.Url, r.Timeout, r.FastHttp = 1, srv.URL, 3, true
+	e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, &r)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer e.Teardown(context.Background())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Execute(context.Background())
+	}
+}
@@ -0,0 +1,171 @@
+package biz
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"sync"
+	"testing"
+
+	v1 "github.com/peckfly/gopeck/api/pecker/v1"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// peckServiceStandIn answers peck with NotFound for the url "missing" and OK for the others, and records
+// the url and the x-token metadata of every call
+type peckServiceStandIn struct {
+	v1.UnimplementedPeckServiceServer
+	mu    sync.Mutex
+	calls []grpcCall
+}
+
+type grpcCall struct {
+	url   string
+	token string
+}
+
+func (s *peckServiceStandIn) Peck(ctx context.Context, in *v1.PeckRequest) (*v1.PeckReply, error) {
+	call := grpcCall{url: in.Url}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-token")) > 0 {
+		call.token = md.Get("x-token")[0]
+	}
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	s.mu.Unlock()
+	if in.Url == "missing" {
+		return nil, status.Error(codes.NotFound, "url not found")
+	}
+	return &v1.PeckReply{}, nil
+}
+
+func (s *peckServiceStandIn) takeCalls() []grpcCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := s.calls
+	s.calls = nil
+	return calls
+}
+
+func startGrpcStandIn(t *testing.T) (string, *peckServiceStandIn) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	standIn := &peckServiceStandIn{}
+	server := grpc.NewServer()
+	v1.RegisterPeckServiceServer(server, standIn)
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return "grpc://" + lis.Addr().String(), standIn
+}
+
+func peckProtoDescriptor(t *testing.T) string {
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(v1.File_api_pecker_v1_peck_proto),
+	}}
+	b, err := proto.Marshal(fds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestGrpcExecutor(t *testing.T) {
+	url, standIn := startGrpcStandIn(t)
+	descriptor := peckProtoDescriptor(t)
+	tests := []struct {
+		name       string
+		descriptor string
+		body       string
+		statusCode codes.Code
+	}{
+		{name: "ok", descriptor: descriptor, body: `{"url": "found"}`, statusCode: codes.OK},
+		{name: "not found", descriptor: descriptor, body: `{"url": "missing"}`, statusCode: codes.NotFound},
+		{name: "reflection", body: `{"url": "found"}`, statusCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Requester{
+				ProtocolType:    2,
+				Url:             url,
+				Timeout:         1,
+				GrpcMethod:      "pecker.PeckService/peck",
+				ProtoDescriptor: tt.descriptor,
+				Body:            tt.body,
+				Headers:         map[string]string{"x-token": "secret"},
+			}
+			e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer e.Teardown(context.Background())
+			result := e.Execute(context.Background())
+			assert.Equal(t, int(tt.statusCode), result.StatusCode)
+			if tt.statusCode == codes.OK {
+				assert.Empty(t, result.Err)
+			} else {
+				assert.Contains(t, result.Err, "url not found")
+			}
+			assert.Greater(t, result.Duration, int64(0))
+			calls := standIn.takeCalls()
+			if assert.Len(t, calls, 1) {
+				assert.Equal(t, "secret", calls[0].token)
+			}
+		})
+	}
+}
+
+func TestGrpcExecutorDynamicParams(t *testing.T) {
+	url, standIn := startGrpcStandIn(t)
+	r := &Requester{
+		ProtocolType:    2,
+		Url:             url,
+		Timeout:         1,
+		MaxConnections:  2,
+		GrpcMethod:      "pecker.PeckService/peck",
+		ProtoDescriptor: peckProtoDescriptor(t),
+		DynamicParams: []*DynamicParam{
+			{Body: `{"url": "a"}`, Headers: map[string]string{"x-token": "a"}},
+			{Body: `{"url": "missing"}`, Headers: map[string]string{"x-token": "missing"}},
+		},
+	}
+	e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer e.Teardown(context.Background())
+	statusCodes := make(map[int]int)
+	for i := 0; i < 50; i++ {
+		statusCodes[e.Execute(context.Background()).StatusCode]++
+	}
+	assert.Len(t, statusCodes, 2)
+	urls := make(map[string]int)
+	for _, call := range standIn.takeCalls() {
+		// the headers of a param are sent with its own body
+		assert.Equal(t, call.url, call.token)
+		urls[call.url]++
+	}
+	assert.Equal(t, statusCodes[int(codes.OK)], urls["a"])
+	assert.Equal(t, statusCodes[int(codes.NotFound)], urls["missing"])
+}
+
+func TestGrpcExecutorSetup(t *testing.T) {
+	url, _ := startGrpcStandIn(t)
+	cfg := &conf.WorkerStressConf{}
+	_, err := newProtocolExecutor(context.Background(), cfg, &Requester{ProtocolType: 2, Url: url, Timeout: 1,
+		GrpcMethod: "pecker.PeckService/unknown", ProtoDescriptor: peckProtoDescriptor(t)})
+	assert.Error(t, err)
+	_, err = newProtocolExecutor(context.Background(), cfg, &Requester{ProtocolType: 2, Url: url, Timeout: 1,
+		GrpcMethod: "pecker.PeckService/peck", ProtoDescriptor: peckProtoDescriptor(t), Body: `{"unknown": 1}`})
+	assert.Error(t, err)
+}
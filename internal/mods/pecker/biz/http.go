@@ -0,0 +1,274 @@
+package biz
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/log/logc"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// httpExecutor executes http requests with a shared http client
+type httpExecutor struct {
+	conf      *conf.WorkerStressConf
+	r         *Requester
+	client    *http.Client
+	templates []*requestTemplate
+	body      *bodyReader
+	conns     connGauge
+}
+
+func newHttpExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &httpExecutor{conf: conf}
+}
+
+func (e *httpExecutor) Setup(ctx context.Context, r *Requester) error {
+	e.r = r
+	templates, err := newRequestTemplates(r)
+	if err != nil {
+		return err
+	}
+	e.templates = templates
+	e.body, err = newBodyReader(r)
+	if err != nil {
+		return err
+	}
+	if r.MaxConnections == 0 {
+		r.MaxConnections = int32(e.conf.DefaultMaxConnections)
+	}
+	tlsConfig, err := newTlsConfig(e.conf, r)
+	if err != nil {
+		return err
+	}
+	dial, _, err := newDialer(e.conf, r, 0)
+	if err != nil {
+		return err
+	}
+	tr := &http.Transport{
+		// the connections are counted to measure the bytes of the requests on the wire
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dial(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &countingConn{Conn: e.conns.track(conn)}, nil
+		},
+		TLSClientConfig:     tlsConfig,
+		MaxConnsPerHost:     int(r.MaxConnections),
+		MaxIdleConnsPerHost: int(r.MaxIdleConnections),
+		DisableKeepAlives:   r.DisableKeepAlive || r.TlsFreshHandshake,
+		DisableCompression:  r.DisableCompression,
+	}
+	if len(r.Proxy) > 0 {
+		if proxyUrl, err := url.Parse(r.Proxy); err == nil {
+			tr.Proxy = http.ProxyURL(proxyUrl)
+		}
+	}
+	if r.H2 {
+		http2.ConfigureTransport(tr)
+	} else {
+		tr.ForceAttemptHTTP2 = false
+		tr.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	client := &http.Client{Transport: tr, Timeout: time.Duration(r.Timeout) * time.Second}
+	if r.DisableRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	e.client = client
+	return nil
+}
+
+func (e *httpExecutor) Execute(ctx context.Context) *repo.Result {
+	r := e.r
+	s := now()
+	var responseContentLength int64
+	code := noResponseStatus
+	var dnsStart, connStart, tlsStart, resStart, reqStart, delayStart time.Duration
+	var dnsDuration, connDuration, tlsDuration, resDuration, reqDuration, delayDuration time.Duration
+	var counter *countingConn
+	var readBase, writtenBase, requestBytes, responseBytes int64
+	// the request is written by the write loop of the transport
+	var wroteBytes atomic.Int64
+	var tlsState *tls.ConnectionState
+	// got is false if the request failed before it got a connection
+	var got, reused bool
+	template := e.templates[0]
+	if len(e.templates) > 1 {
+		template = e.templates[rand.Intn(len(e.templates))]
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			dnsStart = now()
+		},
+		DNSDone: func(dnsInfo httptrace.DNSDoneInfo) {
+			dnsDuration = now() - dnsStart
+		},
+		ConnectStart: func(network, addr string) {
+			connStart = now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				connDuration = now() - connStart
+			}
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = now()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			tlsDuration = now() - tlsStart
+		},
+		GotConn: func(connInfo httptrace.GotConnInfo) {
+			reqStart = now()
+			got, reused = true, connInfo.Reused
+			// the bytes of a new connection include the handshakes of the request
+			if counter = connCounter(connInfo.Conn); counter != nil && connInfo.Reused {
+				readBase, writtenBase = counter.read.Load(), counter.written.Load()
+			}
+			// the handshake is reported by the request which uses the new connection
+			if state, ok := tlsConnState(connInfo.Conn); ok && !connInfo.Reused {
+				tlsState = &state
+			}
+		},
+		WroteRequest: func(w httptrace.WroteRequestInfo) {
+			reqDuration = now() - reqStart
+			// the connection may be reused by another request once the response is read
+			if counter != nil {
+				wroteBytes.Store(counter.written.Load() - writtenBase)
+			}
+			delayStart = now()
+		},
+		GotFirstResponseByte: func() {
+			delayDuration = now() - delayStart
+			resStart = now()
+		},
+	}
+	req := template.request(httptrace.WithClientTrace(ctx, trace))
+	requestTime := time.Now().Unix()
+	resp, err := e.client.Do(req)
+	var errorStr string
+	var respBody []byte
+	var stream *streamResult
+	if err == nil {
+		code = resp.StatusCode
+		body := io.Reader(resp.Body)
+		if r.MaxBodySize > 0 {
+			body = io.LimitReader(body, r.MaxBodySize)
+		}
+		if r.StreamType > 0 {
+			stream, err = readStream(body, enums.ResponseStreamType(r.StreamType), r.responseChecker != nil)
+			respBody = stream.body
+			responseContentLength = stream.size
+		} else {
+			responseContentLength, respBody, err = e.body.read(body)
+		}
+		resDuration = now() - resStart
+		if err != nil {
+			errorStr = cutError(err, e.conf.ErrorCutLength)
+		}
+		resp.Body.Close()
+		if resp.ProtoMajor == 2 {
+			// the streams share the connection, the bytes are estimated in http/1.1
+			requestBytes, responseBytes = template.size, responseHeaderSize(resp)+responseContentLength
+			counter = nil
+		}
+	} else {
+		errorStr = cutError(err, e.conf.ErrorCutLength)
+	}
+	if counter != nil {
+		if requestBytes = wroteBytes.Load(); requestBytes == 0 {
+			requestBytes = counter.written.Load() - writtenBase
+		}
+		responseBytes = counter.read.Load() - readBase
+	}
+	var bodyResult string
+	if r.responseChecker != nil {
+		err = r.responseChecker.ExecuteScript(func(executor any) {
+			bodyResult = executor.(func(string) string)(string(respBody))
+		})
+		if err != nil {
+			logc.Error(ctx, "failed to execute script", zap.Error(err))
+		}
+	}
+	t := now()
+	result := &repo.Result{
+		Err:                   errorStr,
+		StatusCode:            code,
+		Duration:              t - s,
+		ResponseContentLength: responseContentLength,
+		TimeStamp:             requestTime,
+		BodyCheckResult:       bodyResult,
+		DnsDuration:           dnsDuration,
+		ConnectDuration:       connDuration,
+		TlsDuration:           tlsDuration,
+		WriteDuration:         reqDuration,
+		WaitDuration:          delayDuration,
+		TransferDuration:      resDuration,
+		RequestBytes:          requestBytes,
+		ResponseBytes:         responseBytes,
+		NewConn:               got && !reused,
+		ReusedConn:            got && reused,
+		OpenConns:             e.conns.open.Load(),
+	}
+	if tlsState != nil {
+		result.TlsHandshake = true
+		result.TlsResumed = tlsState.DidResume
+		result.TlsVersion = tls.VersionName(tlsState.Version)
+		result.TlsCipher = tls.CipherSuiteName(tlsState.CipherSuite)
+	}
+	if stream != nil {
+		result.FirstByteDuration = resStart - s
+		if len(stream.events) > 0 {
+			result.FirstEventDuration = stream.events[0] - s
+			for i := 1; i < len(stream.events); i++ {
+				result.EventGaps = append(result.EventGaps, stream.events[i]-stream.events[i-1])
+			}
+		}
+		result.StreamDuration = stream.end - resStart
+	}
+	return result
+}
+
+func (e *httpExecutor) Teardown(ctx context.Context) {
+	e.client.CloseIdleConnections()
+}
+
+func constructRequest(method string, urlStr string, headers map[string]string, query map[string]string, body string) (*http.Request, error) {
+	reqURL, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		q := reqURL.Query()
+		for k, v := range query {
+			q.Add(k, v)
+		}
+		reqURL.RawQuery = q.Encode()
+	}
+
+	requestBody := strings.NewReader(body)
+
+	req, err := http.NewRequest(method, reqURL.String(), requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return req, nil
+}
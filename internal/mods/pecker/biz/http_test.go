@@ -0,0 +1,191 @@
+package biz
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/tlsx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpExecutorPhases(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write(make([]byte, 64*1024))
+	}))
+	defer srv.Close()
+	r := &Requester{ProtocolType: 1, Method: http.MethodGet, Url: srv.URL, Timeout: 3}
+	e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+	assert.NoError(t, err)
+	defer e.Teardown(context.Background())
+
+	first := e.Execute(context.Background())
+	assert.Empty(t, first.Err)
+	// the url is an ip, there is no dns lookup
+	assert.Zero(t, first.DnsDuration)
+	assert.Positive(t, first.ConnectDuration)
+	assert.Positive(t, first.TlsDuration)
+	assert.Positive(t, first.WriteDuration)
+	assert.GreaterOrEqual(t, first.WaitDuration, 20*time.Millisecond)
+	assert.Positive(t, first.TransferDuration)
+	assert.LessOrEqual(t, first.ConnectDuration+first.TlsDuration+first.WriteDuration+first.WaitDuration+first.TransferDuration, first.Duration)
+
+	// the reused connection has no connect and tls phase
+	second := e.Execute(context.Background())
+	assert.Empty(t, second.Err)
+	assert.Zero(t, second.ConnectDuration)
+	assert.Zero(t, second.TlsDuration)
+	assert.GreaterOrEqual(t, second.WaitDuration, 20*time.Millisecond)
+}
+
+func TestHttpExecutorBytes(t *testing.T) {
+	body := strings.Repeat("gopeck", 10000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(body))
+		_ = zw.Close()
+	}))
+	defer srv.Close()
+	for _, fastHttp := range []bool{false, true} {
+		r := *templateRequester
+		r.Url, r.Timeout, r.FastHttp, r.BodyMode = srv.URL, 3, fastHttp, int32(enums.BufferBody)
+		e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, &r)
+		assert.NoError(t, err)
+		// the second request reuses the connection and counts its own bytes only
+		for i := 0; i < 2; i++ {
+			result := e.Execute(context.Background())
+			assert.Empty(t, result.Err)
+			assert.Greater(t, result.RequestBytes, int64(len(r.Body)), fastHttp)
+			assert.Less(t, result.RequestBytes, int64(len(r.Body)+500), fastHttp)
+			// the compressed body on the wire is much smaller than the body read
+			assert.Positive(t, result.ResponseBytes, fastHttp)
+			assert.Less(t, result.ResponseBytes, int64(len(body)/10), fastHttp)
+			assert.Equal(t, int64(len(body)), result.ResponseContentLength, fastHttp)
+		}
+		e.Teardown(context.Background())
+	}
+}
+
+func TestHttpExecutorHostMapping(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.ServerName))
+	}))
+	defer srv.Close()
+	port := srv.URL[strings.LastIndex(srv.URL, ":")+1:]
+	// the certificate of the test server is valid for example.com, which is verified by the sni kept
+	c := &conf.WorkerStressConf{ErrorCutLength: 100, TlsSecret: "gopeck-secret"}
+	ca, err := tlsx.EncryptPem(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})), c.TlsSecret)
+	assert.NoError(t, err)
+	cases := []*Requester{
+		{Url: "https://example.com:" + port, HostMapping: "example.com:" + port + ":127.0.0.1"},
+		// the next address is dialed if the first is refused
+		{Url: "https://example.com:" + port, HostMapping: "example.com:*:127.0.0.2,127.0.0.1"},
+		// the dns cache resolves the other hosts
+		{Url: "https://localhost:" + port, TlsServerName: "example.com", DnsCacheTtl: 60},
+	}
+	for i, r := range cases {
+		for _, fastHttp := range []bool{false, true} {
+			r.Method, r.Timeout, r.TlsCa, r.FastHttp = http.MethodGet, 1, ca, fastHttp
+			e, err := newProtocolExecutor(context.Background(), c, r)
+			assert.NoError(t, err)
+			result := e.Execute(context.Background())
+			e.Teardown(context.Background())
+			assert.Empty(t, result.Err, "%d %v", i, fastHttp)
+			assert.Equal(t, int64(len("example.com")), result.ResponseContentLength, "%d %v", i, fastHttp)
+		}
+	}
+
+	_, err = newProtocolExecutor(context.Background(), c, &Requester{Url: srv.URL, HostMapping: "example.com"})
+	assert.Error(t, err)
+}
+
+func TestHttpExecutorConns(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("gopeck"))
+	}))
+	defer srv.Close()
+	for _, disableKeepAlive := range []bool{false, true} {
+		r := &Requester{Method: http.MethodGet, Url: srv.URL, Timeout: 3, DisableKeepAlive: disableKeepAlive}
+		e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+		assert.NoError(t, err)
+		ar := repo.NewAggeRate()
+		ar.Node = "127.0.0.1:9000"
+		for i := 0; i < 3; i++ {
+			result := e.Execute(context.Background())
+			assert.Empty(t, result.Err)
+			assert.Equal(t, i == 0 || disableKeepAlive, result.NewConn)
+			assert.Equal(t, i > 0 && !disableKeepAlive, result.ReusedConn)
+			ar.Add(result)
+		}
+		e.Teardown(context.Background())
+		if disableKeepAlive {
+			assert.Equal(t, map[string]int64{ar.Node: 3}, ar.NewConnMap)
+			assert.Empty(t, ar.ReusedConnMap)
+		} else {
+			assert.Equal(t, map[string]int64{ar.Node: 1}, ar.NewConnMap)
+			assert.Equal(t, map[string]int64{ar.Node: 2}, ar.ReusedConnMap)
+			assert.Equal(t, map[string]int64{ar.Node: 1}, ar.PeakOpenConnMap)
+			assert.Zero(t, e.(*httpExecutor).conns.open.Load())
+		}
+	}
+
+	// the counts of the nodes are summed while the peaks are the max of every node
+	a, b := repo.NewAggeRate(), repo.NewAggeRate()
+	a.Node, b.Node = "node1", "node2"
+	a.Add(&repo.Result{NewConn: true, OpenConns: 3})
+	a.Add(&repo.Result{ReusedConn: true, OpenConns: 5})
+	b.Add(&repo.Result{NewConn: true, OpenConns: 2})
+	merged := repo.NewAggeRate()
+	merged.Merge(a)
+	merged.Merge(b)
+	merged.Merge(a)
+	assert.Equal(t, map[string]int64{"node1": 2, "node2": 1}, merged.NewConnMap)
+	assert.Equal(t, map[string]int64{"node1": 5, "node2": 2}, merged.PeakOpenConnMap)
+	assert.Equal(t, int64(7), repo.SumMap(merged.PeakOpenConnMap))
+}
+
+func TestHttpExecutorNoResponse(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	for _, fastHttp := range []bool{false, true} {
+		r := &Requester{Method: http.MethodGet, Url: srv.URL, Timeout: 1, FastHttp: fastHttp}
+		e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+		assert.NoError(t, err)
+		result := e.Execute(context.Background())
+		e.Teardown(context.Background())
+		assert.NotEmpty(t, result.Err, fastHttp)
+		// the refused request is not taken as the status 0, e.g. the OK of grpc
+		assert.Equal(t, noResponseStatus, result.StatusCode, fastHttp)
+	}
+}
+
+func BenchmarkHttpExecutor(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+	r := *templateRequester
+	r.ProtocolType, r.Url, r.Timeout = 1, srv.URL, 3
+	e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, &r)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer e.Teardown(context.Background())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Execute(context.Background())
+	}
+}
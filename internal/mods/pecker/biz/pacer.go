@@ -2,7 +2,9 @@ package biz
 
 import (
 	"fmt"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -144,3 +146,227 @@ func (p LinearPacer) hits(t time.Duration) float64 {
 
 	return (a*math.Pow(x, 2))/2 + b*x
 }
+
+// PoissonPacer paces an attack with poisson arrivals of the given mean rate,
+// the gaps between hits are exponentially distributed. It is not safe for concurrent use.
+type PoissonPacer struct {
+	Freq float64 // Mean hits per second
+
+	arrivals uint64
+	next     time.Duration
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p *PoissonPacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	switch {
+	case p.Freq == 0:
+		return 0, false // Zero value = infinite rate
+	case p.Freq < 0:
+		return 0, true
+	}
+	// the arrival time of the next hit is the sum of its preceding gaps
+	for p.arrivals <= hits {
+		p.next += time.Duration(rand.ExpFloat64() / p.Freq * 1e9)
+		p.arrivals++
+	}
+	return p.next - elapsed, false
+}
+
+// Rate returns the mean hit rate of the PoissonPacer.
+func (p *PoissonPacer) Rate(elapsed time.Duration) float64 {
+	return p.Freq
+}
+
+// SinePacer paces an attack with a rate varying as a sine wave around the mean rate.
+type SinePacer struct {
+	Mean   float64       // Mean hits per second
+	Amp    float64       // Amplitude of hits per second, less than the mean
+	Period time.Duration // Period of the sine wave
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p SinePacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	if p.Period <= 0 {
+		return ConstantPacer{int(p.Mean), time.Second}.Pace(elapsed, hits)
+	}
+	return paceByHits(elapsed, hits, p.hits, p.Period)
+}
+
+// Rate returns the SinePacer's instantaneous hit rate at the given elapsed duration.
+func (p SinePacer) Rate(elapsed time.Duration) float64 {
+	return p.Mean + p.Amp*math.Sin(2*math.Pi*elapsed.Seconds()/p.Period.Seconds())
+}
+
+// hits returns the integral of the rate in the elapsed duration.
+func (p SinePacer) hits(t time.Duration) float64 {
+	w := 2 * math.Pi / p.Period.Seconds()
+	return p.Mean*t.Seconds() + p.Amp/w*(1-math.Cos(w*t.Seconds()))
+}
+
+// SawtoothPacer paces an attack with a rate rising in steps from the low rate to the high rate
+// in every period, and dropping back to the low rate at the beginning of the next period.
+type SawtoothPacer struct {
+	Low    float64 // Hits per second of the first step
+	High   float64 // Hits per second of the last step
+	Steps  int
+	Period time.Duration
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p SawtoothPacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	if p.Period <= 0 {
+		return ConstantPacer{int(p.Low), time.Second}.Pace(elapsed, hits)
+	}
+	return paceByHits(elapsed, hits, p.hits, p.Period)
+}
+
+// Rate returns the SawtoothPacer's instantaneous hit rate at the given elapsed duration.
+func (p SawtoothPacer) Rate(elapsed time.Duration) float64 {
+	stepDuration := p.Period / time.Duration(p.steps())
+	return p.stepRate(int((elapsed % p.Period) / stepDuration))
+}
+
+// hits returns the integral of the rate in the elapsed duration.
+func (p SawtoothPacer) hits(t time.Duration) float64 {
+	steps := p.steps()
+	stepSeconds := p.Period.Seconds() / float64(steps)
+	var periodHits float64
+	for i := 0; i < steps; i++ {
+		periodHits += p.stepRate(i) * stepSeconds
+	}
+	total := float64(t/p.Period) * periodHits
+	rest := (t % p.Period).Seconds()
+	for i := 0; i < steps && rest > 0; i++ {
+		total += p.stepRate(i) * min(rest, stepSeconds)
+		rest -= stepSeconds
+	}
+	return total
+}
+
+func (p SawtoothPacer) steps() int {
+	return max(p.Steps, 2)
+}
+
+func (p SawtoothPacer) stepRate(i int) float64 {
+	return p.Low + (p.High-p.Low)*float64(i)/float64(p.steps()-1)
+}
+
+// StagePacer paces an attack by the targets of the load profile, the rate moves linearly or jumps
+// to the target of every stage.
+type StagePacer struct {
+	Profile loadProfile
+}
+
+// Pace determines the length of time to sleep until the next hit is sent.
+func (p StagePacer) Pace(elapsed time.Duration, hits uint64) (time.Duration, bool) {
+	du := p.Profile.duration()
+	if p.Profile.hits(du) < float64(hits+1) {
+		// no more hits in the rest stages
+		return 0, true
+	}
+	return paceByHits(elapsed, hits, p.Profile.hits, max(du-elapsed, 0))
+}
+
+// Rate returns the StagePacer's instantaneous hit rate at the given elapsed duration.
+func (p StagePacer) Rate(elapsed time.Duration) float64 {
+	return p.Profile.target(elapsed)
+}
+
+// paceByHits determines the sleep time until the next hit by the hits function, which is the
+// integral of the rate and is non-decreasing, so the time of the next hit is found by bisection
+// within the lookahead duration.
+func paceByHits(elapsed time.Duration, hits uint64, hitsFunc func(time.Duration) float64, lookahead time.Duration) (time.Duration, bool) {
+	if hitsFunc(elapsed) >= float64(hits+1) {
+		// Running behind, send next hit immediately.
+		return 0, false
+	}
+	return scheduleByHits(hits, hitsFunc, elapsed, elapsed+lookahead) - elapsed, false
+}
+
+// scheduleByHits returns the earliest time in [lo, hi] when the hits function reaches the next hit,
+// or hi if it is not reached.
+func scheduleByHits(hits uint64, hitsFunc func(time.Duration) float64, lo, hi time.Duration) time.Duration {
+	target := float64(hits + 1)
+	if hitsFunc(hi) < target {
+		return hi
+	}
+	if hitsFunc(lo) >= target {
+		return lo
+	}
+	for hi-lo > time.Microsecond {
+		mid := lo + (hi-lo)/2
+		if hitsFunc(mid) >= target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
+
+// scheduledAt returns the intended elapsed time of the next hit on the schedule of the pacer, given
+// the wait returned by Pace. A pacer running behind returns zero wait, but the intended time is still
+// the time on the schedule, so that the delay of sending late is not omitted from the response time.
+// The lower bound is the intended time of the previous hit.
+func scheduledAt(p Pacer, lower, elapsed time.Duration, hits uint64, wait time.Duration) time.Duration {
+	planned := elapsed + max(wait, 0)
+	var at time.Duration
+	switch p := p.(type) {
+	case ConstantPacer:
+		if p.Per <= 0 || p.Freq <= 0 {
+			return planned
+		}
+		at = time.Duration(hits+1) * time.Duration(p.Per.Nanoseconds()/int64(p.Freq))
+	case LinearPacer:
+		if hits == 0 {
+			return planned
+		}
+		at = scheduleByHits(hits, p.hits, min(lower, planned), planned)
+	case *PoissonPacer:
+		if p.Freq <= 0 {
+			return planned
+		}
+		at = p.next
+	case SinePacer:
+		if p.Period <= 0 {
+			return scheduledAt(ConstantPacer{int(p.Mean), time.Second}, lower, elapsed, hits, wait)
+		}
+		at = scheduleByHits(hits, p.hits, min(lower, planned), planned)
+	case SawtoothPacer:
+		if p.Period <= 0 {
+			return scheduledAt(ConstantPacer{int(p.Low), time.Second}, lower, elapsed, hits, wait)
+		}
+		at = scheduleByHits(hits, p.hits, min(lower, planned), planned)
+	case StagePacer:
+		at = scheduleByHits(hits, p.Profile.hits, min(lower, planned), planned)
+	default:
+		return planned
+	}
+	return min(at, planned)
+}
+
+// newPacer builds the pacer selected by the pacer type of the requester, the rates of
+// the pacer are the share of this node
+func newPacer(r *Requester) Pacer {
+	if r.StressMode == int32(enums.Stage) {
+		return StagePacer{Profile: r.profile}
+	}
+	start, end := float64(r.PacerStartNum), float64(r.PacerEndNum)
+	period := time.Duration(r.PacerPeriod) * time.Second
+	switch enums.PacerType(r.PacerType) {
+	case enums.LinearPacer:
+		// zero start rate means infinite rate for the linear pacer, so start with one hit per second at least
+		return LinearPacer{
+			StartAt: Rate{max(int(r.PacerStartNum), 1), time.Second},
+			Slope:   (end - start) / max(float64(r.StressTime), 1),
+		}
+	case enums.PoissonPacer:
+		return &PoissonPacer{Freq: start}
+	case enums.SinePacer:
+		return SinePacer{Mean: start, Amp: end - start, Period: period}
+	case enums.SawtoothPacer:
+		return SawtoothPacer{Low: start, High: end, Steps: int(r.PacerSteps), Period: period}
+	default:
+		return ConstantPacer{int(r.Num), time.Second}
+	}
+}
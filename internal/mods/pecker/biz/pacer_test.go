@@ -0,0 +1,120 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+// simulateHits counts the hits sent by the pacer in the duration without sleeping
+func simulateHits(pacer Pacer, du time.Duration) uint64 {
+	var elapsed time.Duration
+	var hits uint64
+	for {
+		wait, stop := pacer.Pace(elapsed, hits)
+		if stop {
+			break
+		}
+		elapsed += max(wait, 0)
+		if elapsed > du {
+			break
+		}
+		hits++
+	}
+	return hits
+}
+
+func TestNewPacer(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      *Requester
+		du     time.Duration
+		expect float64
+		delta  float64
+	}{
+		{
+			name:   "constant",
+			r:      &Requester{Num: 100},
+			du:     10 * time.Second,
+			expect: 1000,
+			delta:  1,
+		},
+		{
+			name:   "linear",
+			r:      &Requester{Num: 300, StressTime: 10, PacerType: int32(enums.LinearPacer), PacerStartNum: 100, PacerEndNum: 300},
+			du:     10 * time.Second,
+			expect: 2000,
+			delta:  2,
+		},
+		{
+			name:   "poisson",
+			r:      &Requester{Num: 1000, PacerType: int32(enums.PoissonPacer), PacerStartNum: 1000, PacerEndNum: 1000},
+			du:     10 * time.Second,
+			expect: 10000,
+			delta:  400,
+		},
+		{
+			name:   "sine whole periods",
+			r:      &Requester{Num: 150, PacerType: int32(enums.SinePacer), PacerStartNum: 100, PacerEndNum: 150, PacerPeriod: 5},
+			du:     10 * time.Second,
+			expect: 1000,
+			delta:  1,
+		},
+		{
+			name:   "sine rising half period",
+			r:      &Requester{Num: 150, PacerType: int32(enums.SinePacer), PacerStartNum: 100, PacerEndNum: 150, PacerPeriod: 20},
+			du:     10 * time.Second,
+			expect: 1000 + 50*20/3.141592653589793,
+			delta:  1,
+		},
+		{
+			name:   "sawtooth",
+			r:      &Requester{Num: 200, PacerType: int32(enums.SawtoothPacer), PacerStartNum: 100, PacerEndNum: 200, PacerSteps: 3, PacerPeriod: 6},
+			du:     9 * time.Second,
+			expect: 2*(100+150+200) + 2*100 + 150,
+			delta:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := simulateHits(newPacer(tt.r), tt.du)
+			assert.InDelta(t, tt.expect, float64(hits), tt.delta)
+		})
+	}
+}
+
+func TestSawtoothPacerRate(t *testing.T) {
+	p := SawtoothPacer{Low: 100, High: 200, Steps: 3, Period: 6 * time.Second}
+	assert.Equal(t, 100.0, p.Rate(time.Second))
+	assert.Equal(t, 150.0, p.Rate(3*time.Second))
+	assert.Equal(t, 200.0, p.Rate(5*time.Second))
+	assert.Equal(t, 100.0, p.Rate(7*time.Second))
+}
+
+func TestScheduledAt(t *testing.T) {
+	// on schedule, the intended time is the planned time
+	constant := ConstantPacer{100, time.Second}
+	wait, _ := constant.Pace(95*time.Millisecond, 9)
+	assert.Equal(t, 100*time.Millisecond, scheduledAt(constant, 0, 95*time.Millisecond, 9, wait))
+
+	// running behind, the hit is sent at once but the intended time is still on the schedule
+	wait, _ = constant.Pace(2*time.Second, 50)
+	assert.Zero(t, wait)
+	assert.Equal(t, 510*time.Millisecond, scheduledAt(constant, 0, 2*time.Second, 50, wait))
+
+	linear := LinearPacer{StartAt: Rate{100, time.Second}, Slope: 100}
+	wait, _ = linear.Pace(3*time.Second, 149)
+	assert.Zero(t, wait)
+	assert.InDelta(t, float64(time.Second), float64(scheduledAt(linear, 0, 3*time.Second, 149, wait)), float64(time.Millisecond))
+
+	stage := StagePacer{Profile: newLoadProfile(rampSoakSpikeDown)}
+	wait, _ = stage.Pace(20*time.Second, 499)
+	assert.Zero(t, wait)
+	assert.InDelta(t, float64(10*time.Second), float64(scheduledAt(stage, 0, 20*time.Second, 499, wait)), float64(time.Millisecond))
+
+	poisson := &PoissonPacer{Freq: 100}
+	wait, _ = poisson.Pace(0, 0)
+	assert.Equal(t, wait, scheduledAt(poisson, 0, 0, 0, wait))
+}
@@ -0,0 +1,163 @@
+package biz
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/pkg/redisx"
+	"github.com/redis/go-redis/v9"
+	"math/rand"
+	"net"
+	"time"
+)
+
+type (
+	// redisExecutor executes one weighted redis command or pipeline per execution, the latency is
+	// reported by the command name
+	redisExecutor struct {
+		conf        *conf.WorkerStressConf
+		r           *Requester
+		client      *redis.Client
+		commands    []*redisCommand
+		totalWeight int
+	}
+
+	// redisCommand is a command with its arguments rendered by every dynamic param before the stress starts
+	redisCommand struct {
+		name     string
+		pipeline bool
+		weight   int
+		rendered [][][]any
+	}
+)
+
+func newRedisExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &redisExecutor{conf: conf}
+}
+
+func (e *redisExecutor) Setup(ctx context.Context, r *Requester) error {
+	e.r = r
+	options, err := redis.ParseURL(r.Url)
+	if err != nil {
+		return err
+	}
+	if r.MaxConnections == 0 {
+		r.MaxConnections = int32(e.conf.DefaultMaxConnections)
+	}
+	timeout := time.Duration(r.Timeout) * time.Second
+	options.PoolSize = int(r.MaxConnections)
+	options.DialTimeout = timeout
+	options.ReadTimeout = timeout
+	options.WriteTimeout = timeout
+	if options.TLSConfig != nil {
+		// rediss urls use the tls settings of the task
+		tlsConfig, err := newTlsConfig(e.conf, r)
+		if err != nil {
+			return err
+		}
+		if len(tlsConfig.ServerName) == 0 {
+			tlsConfig.ServerName = options.TLSConfig.ServerName
+		}
+		options.TLSConfig = tlsConfig
+	}
+	dial, custom, err := newDialer(e.conf, r, timeout)
+	if err != nil {
+		return err
+	}
+	if custom {
+		// the tls handshake is done on the dialed connection like the default dialer does
+		tlsConfig := options.TLSConfig
+		options.Dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dial(ctx, network, addr)
+			if err != nil || tlsConfig == nil {
+				return conn, err
+			}
+			tlsConn := tls.Client(conn, tlsConfig)
+			if err = tlsConn.HandshakeContext(ctx); err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+			return tlsConn, nil
+		}
+	}
+	commands, err := redisx.ParseCommands(r.RedisCommands)
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		c := &redisCommand{name: command.Name, pipeline: command.IsPipeline(), weight: command.Weight}
+		if len(r.DynamicParams) > 0 {
+			for _, param := range r.DynamicParams {
+				c.rendered = append(c.rendered, command.Render(param.Query))
+			}
+		} else {
+			c.rendered = append(c.rendered, command.Render(nil))
+		}
+		e.commands = append(e.commands, c)
+		e.totalWeight += c.weight
+	}
+	e.client = redis.NewClient(options)
+	return nil
+}
+
+func (e *redisExecutor) Execute(ctx context.Context) *repo.Result {
+	command := e.pick()
+	args := command.rendered[0]
+	if len(command.rendered) > 1 {
+		args = command.rendered[rand.Intn(len(command.rendered))]
+	}
+	requestTime := time.Now().Unix()
+	s := now()
+	var err error
+	if command.pipeline {
+		var cmds []redis.Cmder
+		cmds, err = e.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, cmdArgs := range args {
+				pipe.Do(ctx, cmdArgs...)
+			}
+			return nil
+		})
+		if errors.Is(err, redis.Nil) {
+			// a missing key is a normal reply, the pipeline fails only if any other command fails
+			err = nil
+			for _, cmd := range cmds {
+				if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+					err = cmdErr
+					break
+				}
+			}
+		}
+	} else {
+		err = e.client.Do(ctx, args[0]...).Err()
+		if errors.Is(err, redis.Nil) {
+			err = nil
+		}
+	}
+	return &repo.Result{
+		Err:       cutError(err, e.conf.ErrorCutLength),
+		Duration:  now() - s,
+		TimeStamp: requestTime,
+		Command:   command.name,
+	}
+}
+
+func (e *redisExecutor) Teardown(ctx context.Context) {
+	_ = e.client.Close()
+}
+
+// pick picks a command by the weights
+func (e *redisExecutor) pick() *redisCommand {
+	if len(e.commands) == 1 {
+		return e.commands[0]
+	}
+	n := rand.Intn(e.totalWeight)
+	for _, command := range e.commands {
+		if n < command.weight {
+			return command
+		}
+		n -= command.weight
+	}
+	return e.commands[len(e.commands)-1]
+}
@@ -0,0 +1,82 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRedisTestExecutor(t *testing.T, s *miniredis.Miniredis, commands string, params ...*DynamicParam) ProtocolExecutor {
+	r := &Requester{
+		ProtocolType:   7,
+		Url:            "redis://" + s.Addr(),
+		Timeout:        1,
+		MaxConnections: 2,
+		RedisCommands:  commands,
+		DynamicParams:  params,
+	}
+	e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { e.Teardown(context.Background()) })
+	return e
+}
+
+func TestRedisExecutor(t *testing.T) {
+	s := miniredis.RunT(t)
+	_ = s.Set("user:1", "peck")
+	_ = s.Set("counter", "0")
+	tests := []struct {
+		name     string
+		commands string
+		command  string
+		err      bool
+	}{
+		{name: "get", commands: `[{"command": ["GET", "user:1"]}]`, command: "GET"},
+		// a missing key is a normal reply
+		{name: "missing key", commands: `[{"name": "get_missing", "command": ["GET", "missing"]}]`, command: "get_missing"},
+		{name: "pipeline", commands: `[{"pipeline": [["INCR", "counter"], ["GET", "missing"], ["INCR", "counter"]]}]`, command: "PIPELINE"},
+		{name: "command error", commands: `[{"command": ["HGET", "user:1", "f"]}]`, command: "HGET", err: true},
+		{name: "pipeline error", commands: `[{"pipeline": [["GET", "missing"], ["HGET", "user:1", "f"]]}]`, command: "PIPELINE", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newRedisTestExecutor(t, s, tt.commands).Execute(context.Background())
+			assert.Equal(t, tt.command, result.Command)
+			if tt.err {
+				assert.Contains(t, result.Err, "WRONGTYPE")
+			} else {
+				assert.Empty(t, result.Err)
+			}
+			assert.Greater(t, result.Duration, int64(0))
+		})
+	}
+	// both commands of the pipeline are executed
+	counter, _ := s.Get("counter")
+	assert.Equal(t, "2", counter)
+}
+
+func TestRedisExecutorWeights(t *testing.T) {
+	s := miniredis.RunT(t)
+	e := newRedisTestExecutor(t, s, `[
+		{"command": ["GET", "user:{{id}}"], "weight": 9},
+		{"name": "set_user", "command": ["SET", "user:{{id}}", "{{name}}"]}
+	]`, &DynamicParam{Query: map[string]string{"id": "1", "name": "a"}}, &DynamicParam{Query: map[string]string{"id": "2", "name": "b"}})
+	commands := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		result := e.Execute(context.Background())
+		assert.Empty(t, result.Err)
+		commands[result.Command]++
+	}
+	assert.Len(t, commands, 2)
+	assert.Greater(t, commands["GET"], 4*commands["set_user"])
+	// the arguments are rendered by every param
+	name, _ := s.Get("user:1")
+	assert.Equal(t, "a", name)
+	name, _ = s.Get("user:2")
+	assert.Equal(t, "b", name)
+}
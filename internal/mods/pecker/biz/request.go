@@ -2,7 +2,6 @@ package biz
 
 import (
 	"context"
-	"crypto/tls"
 	"github.com/panjf2000/ants/v2"
 	"github.com/peckfly/gopeck/internal/conf"
 	"github.com/peckfly/gopeck/internal/mods/common/repo"
@@ -10,20 +9,20 @@ import (
 	"github.com/peckfly/gopeck/pkg/atomicx"
 	"github.com/peckfly/gopeck/pkg/interpreter"
 	"github.com/peckfly/gopeck/pkg/log/logc"
-	"github.com/peckfly/gopeck/pkg/netx"
 	"github.com/peckfly/gopeck/pkg/registry"
 	"go.uber.org/zap"
-	"golang.org/x/net/http2"
 	"io"
-	"math/rand"
-	"net/http"
-	"net/http/httptrace"
-	"net/url"
-	"strings"
 	"sync"
 	"time"
 )
 
+const (
+	// stopCheckInterval is the interval of checking stop signal while waiting
+	stopCheckInterval = 500 * time.Millisecond
+	// lateSendThreshold is the delay after the intended send time that a request is counted as sent late
+	lateSendThreshold = 10 * time.Millisecond
+)
+
 var (
 	// control the stop signal of all request
 	stops     = make(map[uint64]*atomicx.AtomicBool)
@@ -39,27 +38,70 @@ type (
 	}
 
 	Requester struct {
-		PlanId              uint64
-		TaskId              uint64
-		StressType          int32
-		StressMode          int32
-		Num                 int32
-		StepIntervalTime    int32
-		Nums                []int32
-		MaxConnections      int32
-		MaxIdleConnections  int32
-		StressTime          int32
-		Timeout             int32
-		Method              string
-		Url                 string
-		Headers             map[string]string
-		Query               string
-		Body                string
-		DynamicParams       []*DynamicParam
-		ResponseCheckScript string
+		PlanId               uint64
+		TaskId               uint64
+		StressType           int32
+		StressMode           int32
+		Num                  int32
+		StepIntervalTime     int32
+		Nums                 []int32
+		MaxConnections       int32
+		MaxIdleConnections   int32
+		StressTime           int32
+		Timeout              int32
+		Method               string
+		Url                  string
+		Headers              map[string]string
+		Query                string
+		Body                 string
+		DynamicParams        []*DynamicParam
+		ResponseCheckScript  string
+		ProtocolType         int32
+		GrpcMethod           string
+		ProtoDescriptor      string
+		WsMessages           string
+		WsCorrelationField   string
+		WsHoldTime           int32
+		StreamType           int32
+		BodyMode             int32
+		BodyChecksum         string
+		TlsVerify            bool
+		TlsCa                string
+		TlsCert              string
+		TlsKey               string
+		TlsServerName        string
+		TlsMinVersion        string
+		TlsMaxVersion        string
+		TlsCipherSuites      string
+		TlsAlpn              string
+		TlsFreshHandshake    bool
+		HostMapping          string
+		DnsCacheTtl          int32
+		SourceAddresses      string
+		PayloadEncoding      int32
+		ResponseDelimiter    string
+		ResponseLength       int32
+		DnsQueryType         string
+		RedisCommands        string
+		ScenarioSteps        string
+		ThinkTimeType        int32
+		ThinkTime            int32
+		ThinkTimeMax         int32
+		PacingTime           int32
+		PacerType            int32
+		PacerStartNum        int32
+		PacerEndNum          int32
+		PacerPeriod          int32
+		PacerSteps           int32
+		StageDurations       []int32
+		StageTransitions     []int32
+		MaxInFlight          int32
+		InFlightPolicy       int32
+		InFlightQueueTimeout int32
 
 		DisableKeepAlive bool
 		H2               bool
+		FastHttp         bool
 		MaxBodySize      int64
 
 		DisableCompression bool
@@ -69,10 +111,14 @@ type (
 		Addr string
 
 		responseChecker *interpreter.EvalInterpreter
+		executor        ProtocolExecutor
+		profile         loadProfile
 		// Writer is where results will be written. If nil, results are written to stdout.
-		Writer  io.Writer
-		results chan *repo.Result
-		start   time.Duration
+		Writer io.Writer
+		// aggregates receives the aggregates of every second from the collectors of the workers
+		aggregates chan *repo.Aggregate
+		collectors *collectors
+		start      time.Duration
 
 		done chan bool
 
@@ -102,6 +148,11 @@ func (b *RequesterUsecase) Request(ctx context.Context, r *Requester) error {
 	if err != nil {
 		return err
 	}
+	r.executor, err = newProtocolExecutor(ctx, &b.conf, r)
+	if err != nil {
+		delete(stops, r.TaskId)
+		return err
+	}
 	go b.request(r)
 	return nil
 }
@@ -111,13 +162,13 @@ func (b *Requester) init(conf *conf.WorkerStressConf) error {
 	if b.StressMode == int32(enums.Step) {
 		maxNum = max(b.Nums[len(b.Nums)-1], maxNum)
 	}
-	if enums.StressType(b.StressType) == enums.Rps {
-		b.results = make(chan *repo.Result, min(maxNum*conf.RpsResultChanBlowup, conf.MaxResultChanSize))
-	} else {
-		b.results = make(chan *repo.Result, min(maxNum, conf.MaxResultChanSize))
-	}
+	b.aggregates = make(chan *repo.Aggregate, min(maxNum, conf.MaxResultChanSize))
 	b.done = make(chan bool, 1)
+	b.collectors = newCollectors()
 	stops[b.TaskId] = atomicx.ForAtomicBool(false)
+	if b.StressMode == int32(enums.Stage) {
+		b.profile = newLoadProfile(b)
+	}
 	if len(b.ResponseCheckScript) > 0 {
 		evalInterpreter, err := interpreter.NewEvalInterpreter(b.ResponseCheckScript)
 		if err != nil {
@@ -135,65 +186,37 @@ func (b *RequesterUsecase) request(r *Requester) {
 	if err != nil {
 		return
 	}
+	stopFlush := r.collectors.flush()
 	b.run(ctx, r)
+	stopFlush()
 	b.done(ctx, r)
 }
 
 func (b *RequesterUsecase) run(ctx context.Context, r *Requester) {
-	if r.MaxConnections == 0 {
-		r.MaxConnections = int32(b.conf.DefaultMaxConnections)
-	}
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
-		MaxConnsPerHost:     int(r.MaxConnections),
-		MaxIdleConnsPerHost: int(r.MaxIdleConnections),
-		DisableKeepAlives:   r.DisableKeepAlive,
-		DisableCompression:  r.DisableCompression,
-	}
-	if len(r.Proxy) > 0 {
-		if proxyUrl, err := url.Parse(r.Proxy); err == nil {
-			tr.Proxy = http.ProxyURL(proxyUrl)
-		}
-	}
-	if r.H2 {
-		http2.ConfigureTransport(tr)
-	} else {
-		tr.ForceAttemptHTTP2 = false
-		tr.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
-	}
-	client := &http.Client{Transport: tr, Timeout: time.Duration(r.Timeout) * time.Second}
-	if r.DisableRedirects {
-		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
-	}
 	r.StartTime = time.Now().Unix()
 	if r.StressType == int32(enums.Rps) {
 		if r.StressMode == int32(enums.Step) {
-			b.runStepRpsRequest(ctx, client, r)
+			b.runStepRpsRequest(ctx, r)
 		} else {
-			b.runRpsRequest(ctx, client, r)
+			b.runRpsRequest(ctx, r)
 		}
 	} else if r.StressType == int32(enums.Concurrency) {
 		if r.StressMode == int32(enums.Step) {
-			b.runStepConcurrencyRequest(ctx, client, r)
+			b.runStepConcurrencyRequest(ctx, r)
+		} else if r.StressMode == int32(enums.Stage) {
+			b.runStageConcurrencyRequest(ctx, r)
 		} else {
-			b.runConcurrencyRequest(ctx, client, r)
+			b.runConcurrencyRequest(ctx, r)
 		}
 	}
 }
 
-func (b *RequesterUsecase) runRpsRequest(ctx context.Context, client *http.Client, r *Requester) {
-	pacer := ConstantPacer{int(r.Num), time.Second}
+func (b *RequesterUsecase) runRpsRequest(ctx context.Context, r *Requester) {
+	pacer := newPacer(r)
 	began, count := time.Now(), uint64(0)
-	taskChan := make(chan struct{})
-	var wg sync.WaitGroup
+	var intended time.Duration
+	d := b.newDispatcher(ctx, r)
 	du := time.Duration(r.StressTime) * time.Second
-	costGoroutineNums := 1
-	wg.Add(1)
-	go b.requestFromChan(ctx, taskChan, &wg, client, r)
 	for {
 		elapsed := time.Since(began)
 		if elapsed > du {
@@ -203,32 +226,22 @@ func (b *RequesterUsecase) runRpsRequest(ctx context.Context, client *http.Clien
 		if stop {
 			break
 		}
-		time.Sleep(wait)
+		intended = scheduledAt(pacer, intended, elapsed, count, wait)
+		sleepUntilStop(r, wait)
 		if stops[r.TaskId].True() {
 			logc.Info(ctx, "stop request got signal", zap.Uint64("task_id", r.TaskId))
 			break
 		}
-		select {
-		case taskChan <- struct{}{}:
-			count++
-			continue
-		default:
-			wg.Add(1)
-			costGoroutineNums++
-			go b.requestFromChan(ctx, taskChan, &wg, client, r)
-		}
-		select {
-		case taskChan <- struct{}{}:
-			count++
-		}
+		// a skipped request is counted as sent, so the pacer does not try to catch up with it
+		d.dispatch(began.Add(intended))
+		count++
 	}
-	close(taskChan)
-	wg.Wait()
+	d.close()
 	elapsed := time.Since(began)
-	logc.Info(ctx, "run request rps mode cost:", zap.Uint64("task_id", r.TaskId), zap.Duration("cost", elapsed), zap.Int("cost_goroutine_num", costGoroutineNums))
+	logc.Info(ctx, "run request rps mode cost:", zap.Uint64("task_id", r.TaskId), zap.Duration("cost", elapsed), zap.Int("cost_goroutine_num", d.workers))
 }
 
-func (b *RequesterUsecase) runConcurrencyRequest(ctx context.Context, client *http.Client, r *Requester) {
+func (b *RequesterUsecase) runConcurrencyRequest(ctx context.Context, r *Requester) {
 	var wg sync.WaitGroup
 	began := time.Now()
 	du := time.Duration(r.StressTime) * time.Second
@@ -236,17 +249,7 @@ func (b *RequesterUsecase) runConcurrencyRequest(ctx context.Context, client *ht
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for {
-				elapsed := time.Since(began)
-				if elapsed > du {
-					break
-				}
-				if stops[r.TaskId].True() {
-					logc.Info(ctx, "stop request got signal", zap.Uint64("task_id", r.TaskId))
-					break
-				}
-				b.goRequest(ctx, client, r)
-			}
+			b.runVirtualUser(ctx, r, began, du, nil)
 		}()
 	}
 	wg.Wait()
@@ -254,149 +257,81 @@ func (b *RequesterUsecase) runConcurrencyRequest(ctx context.Context, client *ht
 	logc.Info(ctx, "run request concurrency mode cost:", zap.Uint64("task_id", r.TaskId), zap.Duration("cost", elapsed))
 }
 
-func (b *RequesterUsecase) requestFromChan(ctx context.Context, taskChan <-chan struct{}, wg *sync.WaitGroup, client *http.Client, r *Requester) {
-	defer wg.Done()
-	for range taskChan {
-		b.goRequest(ctx, client, r)
-	}
-}
-
-func (b *RequesterUsecase) goRequest(ctx context.Context, client *http.Client, r *Requester) {
-	s := now()
-	var responseContentLength int64
-	var code int
-	var dnsStart, connStart, resStart, reqStart, delayStart time.Duration
-	var dnsDuration, connDuration, resDuration, reqDuration, delayDuration time.Duration
-	var req *http.Request
-	var rndIndex int
-	isDynamic := r.DynamicParams != nil && len(r.DynamicParams) > 0
-	var err error
-	if isDynamic {
-		rndIndex = rand.Intn(len(r.DynamicParams))
-		req, err = constructRequest(r.Method, r.Url, r.DynamicParams[rndIndex].Headers, r.DynamicParams[rndIndex].Query, r.DynamicParams[rndIndex].Body)
-	} else {
-		req, err = constructRequest(r.Method, r.Url, r.Headers, netx.ParseQuery(r.Query), r.Body)
-	}
-	if err != nil {
-		logc.Error(ctx, "failed to construct request", zap.Error(err))
-		return
-	}
-	trace := &httptrace.ClientTrace{
-		DNSStart: func(info httptrace.DNSStartInfo) {
-			dnsStart = now()
-		},
-		DNSDone: func(dnsInfo httptrace.DNSDoneInfo) {
-			dnsDuration = now() - dnsStart
-		},
-		GetConn: func(h string) {
-			connStart = now()
-		},
-		GotConn: func(connInfo httptrace.GotConnInfo) {
-			if !connInfo.Reused {
-				connDuration = now() - connStart
-			}
-			reqStart = now()
-		},
-		WroteRequest: func(w httptrace.WroteRequestInfo) {
-			reqDuration = now() - reqStart
-			delayStart = now()
-		},
-		GotFirstResponseByte: func() {
-			delayDuration = now() - delayStart
-			resStart = now()
-		},
-	}
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
-	requestTime := time.Now().Unix()
-	resp, err := client.Do(req)
-	var errorStr string
-	var respBody []byte
-	if err == nil {
-		responseContentLength = resp.ContentLength
-		code = resp.StatusCode
-		body := io.Reader(resp.Body)
-		// TODO read body cost time and lose performance
-		if r.MaxBodySize > 0 {
-			body = io.LimitReader(body, r.MaxBodySize)
+// runVirtualUser runs the iterations of a virtual user until the stress time is over or stopped,
+// waiting the think time between iterations, a non-nil quit ends the virtual user alone
+func (b *RequesterUsecase) runVirtualUser(ctx context.Context, r *Requester, began time.Time, du time.Duration, quit *atomicx.AtomicBool) {
+	t := newThinker(r)
+	c := b.newCollector(r)
+	defer c.close()
+	for {
+		elapsed := time.Since(began)
+		if elapsed > du {
+			break
 		}
-		respBody, err = io.ReadAll(body)
-		if err != nil {
-			errorStr = b.getErrorWithCutLength(err)
+		if stops[r.TaskId].True() {
+			logc.Info(ctx, "stop request got signal", zap.Uint64("task_id", r.TaskId))
+			break
 		}
-		resp.Body.Close()
-	} else {
-		errorStr = b.getErrorWithCutLength(err)
-	}
-	var bodyResult string
-	if r.responseChecker != nil {
-		err = r.responseChecker.ExecuteScript(func(executor any) {
-			bodyResult = executor.(func(string) string)(string(respBody))
-		})
-		if err != nil {
-			logc.Error(ctx, "failed to execute script", zap.Error(err))
+		if quit != nil && quit.True() {
+			break
+		}
+		iterationStart := time.Now()
+		b.goRequest(ctx, r, c, time.Time{})
+		if t != nil {
+			sleepUntilStop(r, min(t.wait(time.Since(iterationStart)), du-time.Since(began)))
 		}
-	}
-	t := now()
-	resDuration = t - resStart
-	finish := t - s
-	if finish > time.Duration(b.conf.MaxTimeoutSecond)*time.Second {
-		finish = time.Duration(b.conf.MaxTimeoutSecond) * time.Second
-	}
-	_, _, _, _, _ = dnsDuration, connDuration, reqDuration, delayDuration, resDuration
-	r.results <- &repo.Result{
-		Err:                   errorStr,
-		StatusCode:            code,
-		Duration:              finish,
-		ResponseContentLength: responseContentLength,
-		TimeStamp:             requestTime,
-		BodyCheckResult:       bodyResult,
-		Stop:                  stops[r.TaskId].True(),
 	}
 }
 
-func (b *RequesterUsecase) getErrorWithCutLength(err error) string {
-	if err == nil {
-		return ""
-	}
-	errorStr := err.Error()
-	if len(errorStr) > b.conf.ErrorCutLength {
-		errorStr = errorStr[len(errorStr)-b.conf.ErrorCutLength:]
+// sleepUntilStop sleeps for the duration, and wakes up early if the task is stopped
+func sleepUntilStop(r *Requester, d time.Duration) {
+	for d > 0 {
+		if stops[r.TaskId].True() {
+			return
+		}
+		step := min(d, stopCheckInterval)
+		time.Sleep(step)
+		d -= step
 	}
-	return errorStr
 }
 
-func constructRequest(method string, urlStr string, headers map[string]string, query map[string]string, body string) (*http.Request, error) {
-	reqURL, err := url.Parse(urlStr)
-	if err != nil {
-		return nil, err
-	}
-	if len(query) > 0 {
-		q := reqURL.Query()
-		for k, v := range query {
-			q.Add(k, v)
+// requestFromChan executes a request for every intended send time from the task channel, and releases
+// an in-flight slot after every request if the slots are limited
+func (b *RequesterUsecase) requestFromChan(ctx context.Context, taskChan <-chan time.Time, slots <-chan struct{}, wg *sync.WaitGroup, r *Requester) {
+	defer wg.Done()
+	c := b.newCollector(r)
+	defer c.close()
+	for intended := range taskChan {
+		b.goRequest(ctx, r, c, intended)
+		if slots != nil {
+			<-slots
 		}
-		reqURL.RawQuery = q.Encode()
 	}
+}
 
-	requestBody := strings.NewReader(body)
-
-	req, err := http.NewRequest(method, reqURL.String(), requestBody)
-	if err != nil {
-		return nil, err
+// goRequest executes one request with the protocol executor and adds the result to the collector, a non-zero
+// intended time is the send time on the pacer schedule, and the response time is measured from it
+func (b *RequesterUsecase) goRequest(ctx context.Context, r *Requester, c *collector, intended time.Time) {
+	sent := time.Now()
+	result := r.executor.Execute(ctx)
+	if result == nil {
+		return
 	}
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	if !intended.IsZero() {
+		delay := max(sent.Sub(intended), 0)
+		result.ResponseDuration = result.Duration + delay
+		result.Late = delay > lateSendThreshold
 	}
-
-	return req, nil
+	result.Stop = stops[r.TaskId].True()
+	c.add(result)
 }
 
 func (b *RequesterUsecase) done(ctx context.Context, r *Requester) {
-	close(r.results)
+	close(r.aggregates)
 	total := now() - r.start
 	<-r.done
 	r.poolFunc.Release()
+	r.executor.Teardown(ctx)
 	delete(stops, r.TaskId)
 	costNum := int(r.Num)
 	if r.StressMode == int32(enums.Step) && len(r.Nums) > 0 {
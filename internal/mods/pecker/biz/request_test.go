@@ -0,0 +1,101 @@
+package biz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/pkg/atomicx"
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepExecutor is a protocol executor taking the service time for every request
+type sleepExecutor struct {
+	service time.Duration
+}
+
+func (e sleepExecutor) Setup(ctx context.Context, r *Requester) error { return nil }
+
+func (e sleepExecutor) Execute(ctx context.Context) *repo.Result {
+	time.Sleep(e.service)
+	return &repo.Result{Duration: e.service}
+}
+
+func (e sleepExecutor) Teardown(ctx context.Context) {}
+
+func TestGoRequestFromIntended(t *testing.T) {
+	b := &RequesterUsecase{}
+	stops[14] = atomicx.ForAtomicBool(false)
+	defer delete(stops, 14)
+	r := &Requester{TaskId: 14, executor: sleepExecutor{5 * time.Millisecond}, aggregates: make(chan *repo.Aggregate, 3)}
+
+	late := b.newCollector(r)
+	b.goRequest(context.Background(), r, late, time.Now().Add(-50*time.Millisecond))
+	assert.Equal(t, int64(1), late.cur.LateNum)
+	assert.GreaterOrEqual(t, late.cur.ResponseDurationMap.Max(), int64(55000))
+
+	onTime := b.newCollector(r)
+	b.goRequest(context.Background(), r, onTime, time.Now().Add(time.Millisecond))
+	assert.Zero(t, onTime.cur.LateNum)
+	assert.Equal(t, onTime.cur.DurationMap, onTime.cur.ResponseDurationMap)
+
+	// without intended time, e.g. concurrency mode, there is no response time
+	closed := b.newCollector(r)
+	b.goRequest(context.Background(), r, closed, time.Time{})
+	assert.Zero(t, closed.cur.LateNum)
+	assert.Zero(t, closed.cur.ResponseDurationMap.TotalCount())
+}
+
+func TestCollector(t *testing.T) {
+	r := &Requester{aggregates: make(chan *repo.Aggregate, 3)}
+	c := (&RequesterUsecase{}).newCollector(r)
+	c.add(&repo.Result{TimeStamp: 100, Duration: time.Millisecond})
+	c.add(&repo.Result{TimeStamp: 100, Duration: 2 * time.Millisecond})
+	assert.Empty(t, r.aggregates)
+
+	// moving to the next second hands the previous one over
+	c.add(&repo.Result{TimeStamp: 101, Duration: time.Millisecond, Stop: true})
+	assert.Len(t, r.aggregates, 1)
+	first := <-r.aggregates
+	assert.Equal(t, int64(100), first.Timestamp)
+	assert.Equal(t, int64(2), first.TotalNum)
+	assert.False(t, first.Stop)
+
+	c.flush()
+	c.flush()
+	assert.Len(t, r.aggregates, 1)
+	second := <-r.aggregates
+	assert.Equal(t, int64(101), second.Timestamp)
+	assert.Equal(t, int64(1), second.TotalNum)
+	assert.True(t, second.Stop)
+}
+
+func TestCollectorFlushIdle(t *testing.T) {
+	r := &Requester{aggregates: make(chan *repo.Aggregate, 3), collectors: newCollectors()}
+	c := (&RequesterUsecase{}).newCollector(r)
+	c.add(&repo.Result{TimeStamp: 100, Duration: time.Millisecond})
+
+	// the aggregate is kept within its second and the grace
+	c.flushBefore(time.Unix(101, 0))
+	c.flushBefore(time.Unix(101, 0).Add(collectorFlushGrace))
+	assert.Empty(t, r.aggregates)
+	c.flushBefore(time.Unix(101, 0).Add(collectorFlushGrace + time.Millisecond))
+	assert.Len(t, r.aggregates, 1)
+	assert.Equal(t, int64(100), (<-r.aggregates).Timestamp)
+
+	// the flusher hands the aggregate of an idle worker over without another request
+	stop := r.collectors.flush()
+	c.add(&repo.Result{TimeStamp: time.Now().Unix(), Duration: time.Millisecond})
+	select {
+	case ar := <-r.aggregates:
+		assert.Equal(t, int64(1), ar.TotalNum)
+	case <-time.After(time.Second + collectorFlushGrace + 2*collectorFlushInterval):
+		assert.Fail(t, "aggregate of the idle worker is not flushed")
+	}
+	stop()
+
+	c.close()
+	assert.Empty(t, r.collectors.set)
+	assert.Empty(t, r.aggregates)
+}
@@ -0,0 +1,23 @@
+package biz
+
+import (
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"time"
+)
+
+// newDialer builds the dial function of the task, the connections are dialed from the source addresses to
+// the addresses of the host mapping or the dns cache, custom is false if it dials like a plain net.Dialer
+func newDialer(conf *conf.WorkerStressConf, r *Requester, timeout time.Duration) (dial netx.DialFunc, custom bool, err error) {
+	mapping, err := netx.ParseHostMapping(r.HostMapping)
+	if err != nil {
+		return nil, false, err
+	}
+	source, err := newSourceDialer(conf, r)
+	if err != nil {
+		return nil, false, err
+	}
+	source.Timeout = timeout
+	resolver := netx.NewResolver(mapping, time.Duration(r.DnsCacheTtl)*time.Second)
+	return resolver.DialContext(source.DialContext), resolver.Enabled() || source.bound(), nil
+}
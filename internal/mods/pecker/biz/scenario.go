@@ -0,0 +1,108 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"github.com/peckfly/gopeck/pkg/scenario"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// scenarioExecutor runs the http steps of the scenario in order per execution, the variables extracted
+// from the responses are filled into the following steps
+type scenarioExecutor struct {
+	httpExecutor
+	steps []*scenario.Step
+}
+
+func newScenarioExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &scenarioExecutor{httpExecutor: httpExecutor{conf: conf}}
+}
+
+func (e *scenarioExecutor) Setup(ctx context.Context, r *Requester) error {
+	steps, err := scenario.ParseSteps(r.ScenarioSteps, r.Url)
+	if err != nil {
+		return err
+	}
+	e.steps = steps
+	return e.httpExecutor.Setup(ctx, r)
+}
+
+func (e *scenarioExecutor) Execute(ctx context.Context) *repo.Result {
+	r := e.r
+	// the query of a dynamic param is the initial variables of the virtual user
+	vars := make(map[string]string)
+	if len(r.DynamicParams) > 0 {
+		for k, v := range r.DynamicParams[rand.Intn(len(r.DynamicParams))].Query {
+			vars[k] = v
+		}
+	}
+	result := &repo.Result{TimeStamp: time.Now().Unix(), Steps: make([]repo.StepResult, 0, len(e.steps))}
+	s := now()
+	for _, step := range e.steps {
+		stepResult, size, err := e.runStep(step, vars)
+		result.Steps = append(result.Steps, stepResult)
+		result.StatusCode = stepResult.StatusCode
+		result.ResponseContentLength += size
+		if err != nil {
+			result.Err = cutError(fmt.Errorf("%s: %w", step.Name, err), e.conf.ErrorCutLength)
+			break
+		}
+	}
+	result.Duration = now() - s
+	return result
+}
+
+// runStep sends the request of the step and extracts the variables from its response
+func (e *scenarioExecutor) runStep(step *scenario.Step, vars map[string]string) (repo.StepResult, int64, error) {
+	stepResult := repo.StepResult{Name: step.Name}
+	headers := make(map[string]string, len(step.Headers))
+	for k, v := range step.Headers {
+		headers[k] = scenario.Render(v, vars)
+	}
+	req, err := constructRequest(step.Method, scenario.Render(step.Url, vars), headers,
+		netx.ParseQuery(scenario.Render(step.Query, vars)), scenario.Render(step.Body, vars))
+	if err != nil {
+		stepResult.Err = cutError(err, e.conf.ErrorCutLength)
+		stepResult.StatusCode = noResponseStatus
+		return stepResult, 0, err
+	}
+	s := now()
+	body, header, code, err := e.do(req)
+	stepResult.Duration = now() - s
+	stepResult.StatusCode = code
+	if err != nil {
+		stepResult.Err = cutError(err, e.conf.ErrorCutLength)
+		return stepResult, int64(len(body)), err
+	}
+	for _, extractor := range step.Extract {
+		value, ok := extractor.Extract(body, header)
+		if !ok {
+			err = fmt.Errorf("extract %s from %s failed", extractor.Var, extractor.From)
+			stepResult.Err = err.Error()
+			return stepResult, int64(len(body)), err
+		}
+		vars[extractor.Var] = value
+	}
+	return stepResult, int64(len(body)), nil
+}
+
+// do sends the request and reads the response body limited by the max body size
+func (e *scenarioExecutor) do(req *http.Request) ([]byte, http.Header, int, error) {
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, nil, noResponseStatus, err
+	}
+	defer resp.Body.Close()
+	body := io.Reader(resp.Body)
+	if e.r.MaxBodySize > 0 {
+		body = io.LimitReader(body, e.r.MaxBodySize)
+	}
+	b, err := io.ReadAll(body)
+	return b, resp.Header, resp.StatusCode, err
+}
@@ -0,0 +1,130 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+// startScenarioServer starts the server of the scenario tests: /login returns the token of the user,
+// /profile returns the uid to the bearer of the token, and /orders counts the calls of the uid 42
+func startScenarioServer(t *testing.T) (string, *atomic.Int32) {
+	var orders atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		var in struct {
+			User string `json:"user"`
+		}
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&in) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("X-Session", "s1")
+		_, _ = w.Write([]byte(`{"data": {"token": "token-` + in.User + `"}}`))
+	})
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-alice" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("uid=42"))
+	})
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("uid") != "42" || r.Header.Get("X-Session") != "s1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		orders.Add(1)
+		_, _ = w.Write([]byte("[]"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL, &orders
+}
+
+func TestScenarioExecutor(t *testing.T) {
+	url, orders := startScenarioServer(t)
+	login := `{"name": "login", "method": "post", "url": "/login", "body": "{\"user\": \"{{user}}\"}",
+		"extract": [{"var": "token", "from": "json", "expr": "$.data.token"}, {"var": "sid", "from": "header", "expr": "X-Session"}]}`
+	ordersStep := `{"name": "orders", "url": "/orders", "query": "uid={{uid}}", "headers": {"X-Session": "{{sid}}"}}`
+	tests := []struct {
+		name       string
+		steps      string
+		statusCode int
+		// codes are the status codes of the executed steps
+		codes  []int
+		err    string
+		orders int32
+	}{
+		{
+			name: "extracted values",
+			steps: `[` + login + `,
+				{"name": "profile", "url": "/profile", "headers": {"Authorization": "Bearer {{token}}"},
+					"extract": [{"var": "uid", "from": "regex", "expr": "uid=(\\d+)"}]},` + ordersStep + `]`,
+			statusCode: http.StatusOK,
+			codes:      []int{http.StatusOK, http.StatusOK, http.StatusOK},
+			orders:     1,
+		},
+		{
+			name: "failed extraction",
+			steps: `[` + login + `,
+				{"name": "profile", "url": "/profile", "headers": {"Authorization": "Bearer {{token}}"},
+					"extract": [{"var": "uid", "from": "regex", "expr": "gid=(\\d+)"}]},` + ordersStep + `]`,
+			statusCode: http.StatusOK,
+			codes:      []int{http.StatusOK, http.StatusOK},
+			err:        "profile: extract uid from regex failed",
+		},
+		{
+			name: "failed request",
+			steps: `[` + login + `,
+				{"name": "profile", "url": "http://127.0.0.1:1/profile"},` + ordersStep + `]`,
+			statusCode: noResponseStatus,
+			codes:      []int{http.StatusOK, noResponseStatus},
+			err:        "profile: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders.Store(0)
+			r := &Requester{
+				ProtocolType:  8,
+				Url:           url,
+				Timeout:       1,
+				ScenarioSteps: tt.steps,
+				DynamicParams: []*DynamicParam{{Query: map[string]string{"user": "alice"}}},
+			}
+			e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, r)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer e.Teardown(context.Background())
+			result := e.Execute(context.Background())
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+			if len(tt.err) > 0 {
+				assert.Contains(t, result.Err, tt.err)
+			} else {
+				assert.Empty(t, result.Err)
+			}
+			if assert.Len(t, result.Steps, len(tt.codes)) {
+				for i, step := range result.Steps {
+					assert.Equal(t, tt.codes[i], step.StatusCode, "%d", i)
+				}
+				assert.Equal(t, "login", result.Steps[0].Name)
+				assert.Empty(t, result.Steps[0].Err)
+				assert.Greater(t, int64(result.Steps[0].Duration), int64(0))
+				if len(tt.err) > 0 {
+					last := result.Steps[len(result.Steps)-1]
+					assert.Equal(t, "profile", last.Name)
+					assert.NotEmpty(t, last.Err)
+				}
+			}
+			assert.Equal(t, tt.orders, orders.Load())
+		})
+	}
+}
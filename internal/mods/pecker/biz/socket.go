@@ -0,0 +1,291 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/consts"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// socketReadSize is the buffer size of reading the response
+const socketReadSize = 64 * 1024
+
+var (
+	// errUnexpectedLength means the response is not of the expected length
+	errUnexpectedLength = errors.New("unexpected response length")
+	// errTrailingBytes means bytes are read after the delimiter, they are not the response of the next request,
+	// so the connection is closed instead of being reused
+	errTrailingBytes = errors.New("unexpected bytes after the response delimiter")
+)
+
+// socketBufferPool holds the buffers of reading the responses, the responses are counted but not kept
+var socketBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, socketReadSize)
+		return &buf
+	},
+}
+
+// socketExecutor writes the binary payload over raw tcp or udp and reads the response, the connections are
+// reused unless keep-alive is disabled
+type socketExecutor struct {
+	conf      *conf.WorkerStressConf
+	r         *Requester
+	network   string
+	address   string
+	payloads  [][]byte
+	delimiter []byte
+	idle      chan net.Conn
+	dial      netx.DialFunc
+	conns     connGauge
+}
+
+func newTcpExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &socketExecutor{conf: conf, network: "tcp"}
+}
+
+func newUdpExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &socketExecutor{conf: conf, network: "udp"}
+}
+
+func (e *socketExecutor) Setup(ctx context.Context, r *Requester) error {
+	e.r = r
+	e.address = strings.TrimPrefix(strings.TrimPrefix(r.Url, consts.TcpScheme), consts.UdpScheme)
+	encoding := netx.PayloadEncoding(r.PayloadEncoding)
+	if len(r.DynamicParams) > 0 {
+		for _, param := range r.DynamicParams {
+			template := r.Body
+			if len(param.Body) > 0 {
+				template = param.Body
+			}
+			payload, err := netx.BuildPayload(template, encoding, param.Query)
+			if err != nil {
+				return err
+			}
+			e.payloads = append(e.payloads, payload)
+		}
+	} else {
+		payload, err := netx.BuildPayload(r.Body, encoding, nil)
+		if err != nil {
+			return err
+		}
+		e.payloads = append(e.payloads, payload)
+	}
+	if len(r.ResponseDelimiter) > 0 {
+		delimiter, err := netx.DecodePayload(r.ResponseDelimiter, encoding)
+		if err != nil {
+			return fmt.Errorf("response delimiter: %w", err)
+		}
+		e.delimiter = delimiter
+	}
+	if r.MaxConnections == 0 {
+		r.MaxConnections = int32(e.conf.DefaultMaxConnections)
+	}
+	e.idle = make(chan net.Conn, max(1, int(r.MaxConnections)))
+	dial, _, err := newDialer(e.conf, r, time.Duration(r.Timeout)*time.Second)
+	if err != nil {
+		return err
+	}
+	e.dial = dial
+	return nil
+}
+
+func (e *socketExecutor) Execute(ctx context.Context) *repo.Result {
+	r := e.r
+	payload := e.payloads[0]
+	if len(e.payloads) > 1 {
+		payload = e.payloads[rand.Intn(len(e.payloads))]
+	}
+	timeout := time.Duration(r.Timeout) * time.Second
+	result := &repo.Result{TimeStamp: time.Now().Unix()}
+	s := now()
+	conn, err := e.getConn(ctx)
+	if err != nil {
+		result.Err = socketErrorClass("dial", err, e.conf.ErrorCutLength)
+		result.Duration = now() - s
+		return result
+	}
+	if conn.new {
+		result.ConnectDuration = now() - s
+	}
+	result.NewConn, result.ReusedConn, result.OpenConns = conn.new, !conn.new, e.conns.open.Load()
+	ws := now()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	written, err := conn.Write(payload)
+	rs := now()
+	result.WriteDuration = rs - ws
+	result.RequestBytes = int64(written)
+	if err != nil {
+		conn.Close()
+		result.Err = socketErrorClass("write", err, e.conf.ErrorCutLength)
+		result.Duration = now() - s
+		return result
+	}
+	n, err := e.readResponse(conn)
+	t := now()
+	result.ReadDuration = t - rs
+	result.Duration = t - s
+	result.ResponseContentLength = n
+	result.ResponseBytes = n
+	if err != nil {
+		conn.Close()
+		result.Err = socketErrorClass("read", err, e.conf.ErrorCutLength)
+		return result
+	}
+	e.putConn(conn)
+	return result
+}
+
+func (e *socketExecutor) Teardown(ctx context.Context) {
+	for {
+		select {
+		case conn := <-e.idle:
+			conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+// socketConn marks whether the connection is newly dialed
+type socketConn struct {
+	net.Conn
+	new bool
+}
+
+// getConn takes an idle connection, or dials a new one if there is none
+func (e *socketExecutor) getConn(ctx context.Context) (*socketConn, error) {
+	if !e.r.DisableKeepAlive {
+		select {
+		case conn := <-e.idle:
+			return &socketConn{Conn: conn}, nil
+		default:
+		}
+	}
+	conn, err := e.dial(ctx, e.network, e.address)
+	if err != nil {
+		return nil, err
+	}
+	return &socketConn{Conn: e.conns.track(conn), new: true}, nil
+}
+
+// putConn returns the connection to the idle pool, it is closed if keep-alive is disabled or the pool is full
+func (e *socketExecutor) putConn(conn *socketConn) {
+	if e.r.DisableKeepAlive {
+		conn.Close()
+		return
+	}
+	select {
+	case e.idle <- conn.Conn:
+	default:
+		conn.Close()
+	}
+}
+
+// readResponse reads the response until the delimiter or the expected length is read, a udp response is
+// one datagram and a tcp response without delimiter or expected length is what the first read returns
+func (e *socketExecutor) readResponse(conn net.Conn) (int64, error) {
+	bufp := socketBufferPool.Get().(*[]byte)
+	defer socketBufferPool.Put(bufp)
+	buf := *bufp
+	expected := int(e.r.ResponseLength)
+	if e.network == "udp" || (len(e.delimiter) == 0 && expected == 0) {
+		n, err := conn.Read(buf)
+		if err == nil && expected > 0 && n != expected {
+			err = errUnexpectedLength
+		}
+		return int64(n), err
+	}
+	if expected > 0 {
+		var size int64
+		for remaining := expected; remaining > 0; {
+			n, err := io.ReadFull(conn, buf[:min(remaining, len(buf))])
+			size += int64(n)
+			remaining -= n
+			if err != nil {
+				return size, err
+			}
+		}
+		return size, nil
+	}
+	return readUntilDelimiter(conn, buf, e.delimiter)
+}
+
+// readUntilDelimiter reads until the delimiter is read, only the new bytes and the tail of the bytes read
+// before, which may hold the beginning of the delimiter, are scanned. The bytes after the delimiter are
+// rejected by errTrailingBytes, since they would be taken as the response of the next request.
+func readUntilDelimiter(conn net.Conn, buf, delimiter []byte) (int64, error) {
+	var size int64
+	// carry is the tail of the bytes read before, joint is the carry followed by the head of the new bytes
+	keep := len(delimiter) - 1
+	carry := make([]byte, 0, 2*keep)
+	joint := make([]byte, 0, 2*keep)
+	for {
+		n, err := conn.Read(buf)
+		size += int64(n)
+		chunk := buf[:n]
+		end := -1
+		joint = append(append(joint[:0], carry...), chunk[:min(n, keep)]...)
+		if i := bytes.Index(joint, delimiter); i >= 0 {
+			end = i + len(delimiter) - len(carry)
+		} else if i = bytes.Index(chunk, delimiter); i >= 0 {
+			end = i + len(delimiter)
+		}
+		if end >= 0 {
+			if end < n {
+				return size, errTrailingBytes
+			}
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+		carry = append(carry, chunk[max(0, n-keep):]...)
+		if over := len(carry) - keep; over > 0 {
+			carry = carry[:copy(carry, carry[over:])]
+		}
+	}
+}
+
+// socketErrorClass buckets the socket error by operation and cause, so that the error map is not
+// flooded by the addresses and ports in the error messages
+func socketErrorClass(op string, err error, cutLength int) string {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout(), errors.Is(err, os.ErrDeadlineExceeded):
+		return op + ": timeout"
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return op + ": connection closed by peer"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return op + ": connection refused"
+	case errors.Is(err, syscall.ECONNRESET):
+		return op + ": connection reset"
+	case errors.Is(err, syscall.EPIPE):
+		return op + ": broken pipe"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return op + ": unreachable"
+	case isPortsExhausted(err):
+		return op + ": " + portsExhausted
+	case errors.Is(err, errUnexpectedLength):
+		return op + ": " + errUnexpectedLength.Error()
+	case errors.Is(err, errTrailingBytes):
+		return op + ": " + errTrailingBytes.Error()
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return op + ": dns error"
+	}
+	return op + ": " + cutError(err, cutLength)
+}
@@ -0,0 +1,137 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+// readerConn is a connection reading from the reader
+type readerConn struct {
+	net.Conn
+	io.Reader
+}
+
+func (c readerConn) Read(p []byte) (int, error) {
+	return c.Reader.Read(p)
+}
+
+func TestReadUntilDelimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		delimiter string
+		size      int64
+		err       error
+	}{
+		{name: "one byte delimiter", response: "gopeck\n", delimiter: "\n", size: 7},
+		{name: "delimiter", response: "gopeck\r\n\r\n", delimiter: "\r\n\r\n", size: 10},
+		{name: "partial delimiter in the body", response: "go\r\npeck\r\n\r\n", delimiter: "\r\n\r\n", size: 12},
+		{name: "no delimiter", response: "gopeck\r\n", delimiter: "\r\n\r\n", size: 8, err: io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the delimiter is split across the reads of one byte, or across the reads of the small buffer
+			for _, oneByte := range []bool{false, true} {
+				for _, bufSize := range []int{1, 3, socketReadSize} {
+					reader := io.Reader(strings.NewReader(tt.response))
+					if oneByte {
+						reader = iotest.OneByteReader(reader)
+					}
+					size, err := readUntilDelimiter(readerConn{Reader: reader}, make([]byte, bufSize), []byte(tt.delimiter))
+					if tt.err == nil {
+						assert.NoError(t, err, "%d", bufSize)
+					} else {
+						assert.ErrorIs(t, err, tt.err, "%d", bufSize)
+					}
+					assert.Equal(t, tt.size, size, "%d", bufSize)
+				}
+			}
+		})
+	}
+}
+
+func TestReadUntilDelimiterTrailing(t *testing.T) {
+	// the bytes read with the delimiter are rejected
+	size, err := readUntilDelimiter(readerConn{Reader: strings.NewReader("gopeck\r\n\r\nnext")}, make([]byte, socketReadSize), []byte("\r\n\r\n"))
+	assert.ErrorIs(t, err, errTrailingBytes)
+	assert.Equal(t, int64(14), size)
+	// the bytes after the read of the delimiter are not read
+	size, err = readUntilDelimiter(readerConn{Reader: strings.NewReader("gopeck\r\n\r\nnext")}, make([]byte, 5), []byte("\r\n\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), size)
+}
+
+func TestSocketExecutor(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+	// the server answers every line with the long response, which is read in pieces, followed by the delimiter,
+	// and the line "twice" with two short responses at once
+	long := bytes.Repeat([]byte("x"), 3*socketReadSize)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 64)
+				for {
+					n, err := conn.Read(buf)
+					if err != nil {
+						return
+					}
+					response := append(append([]byte{}, long...), "END"...)
+					if strings.HasPrefix(string(buf[:n]), "twice") {
+						response = []byte("okENDokEND")
+					}
+					if _, err = conn.Write(response); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	tests := []struct {
+		name string
+		r    *Requester
+		size int64
+		err  string
+	}{
+		{name: "delimiter", r: &Requester{Body: hex.EncodeToString([]byte("once\n")), ResponseDelimiter: hex.EncodeToString([]byte("END"))}, size: int64(len(long) + 3)},
+		{name: "expected length", r: &Requester{Body: hex.EncodeToString([]byte("once\n")), ResponseLength: int32(len(long) + 3)}, size: int64(len(long) + 3)},
+		{name: "trailing response", r: &Requester{Body: hex.EncodeToString([]byte("twice\n")), ResponseDelimiter: hex.EncodeToString([]byte("END"))}, err: "read: " + errTrailingBytes.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.ProtocolType, tt.r.Url, tt.r.Timeout = int32(enums.Tcp), "tcp://"+ln.Addr().String(), 1
+			e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, tt.r)
+			assert.NoError(t, err)
+			defer e.Teardown(context.Background())
+			for i := 0; i < 2; i++ {
+				result := e.Execute(context.Background())
+				assert.Equal(t, tt.err, result.Err, "%d", i)
+				if len(tt.err) > 0 {
+					// the connection is not reused after the trailing bytes
+					assert.True(t, result.NewConn, "%d", i)
+					continue
+				}
+				// the connection is reused after the whole response is read
+				assert.Equal(t, tt.size, result.ResponseBytes, "%d", i)
+				assert.Equal(t, i > 0, result.ReusedConn, "%d", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"net"
+	"strings"
+	"sync/atomic"
+	"syscall"
+)
+
+// portsExhausted is the error class of the dials failed for no free ephemeral port of the source address
+const portsExhausted = "local ports exhausted"
+
+// sourceDialer dials from the local source addresses in turn, so that every address has its own ephemeral
+// ports against one target. The addresses of the task take the place of the ones of the pecker config.
+type sourceDialer struct {
+	net.Dialer
+	addrs []net.IP
+	next  atomic.Uint32
+}
+
+func newSourceDialer(conf *conf.WorkerStressConf, r *Requester) (*sourceDialer, error) {
+	addrs := r.SourceAddresses
+	if len(addrs) == 0 {
+		addrs = strings.Join(conf.SourceAddresses, ",")
+	}
+	ips, err := netx.ParseIPs(addrs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		// the address is checked by binding it, so that an address not assigned to the pecker is not
+		// taken as the exhaustion of the ports later
+		l, err := net.ListenPacket("udp", net.JoinHostPort(ip.String(), "0"))
+		if err != nil {
+			return nil, fmt.Errorf("source address %s is not available: %w", ip, err)
+		}
+		_ = l.Close()
+	}
+	return &sourceDialer{addrs: ips}, nil
+}
+
+// bound returns whether the dials are bound to the source addresses
+func (d *sourceDialer) bound() bool {
+	return len(d.addrs) > 0
+}
+
+// DialContext dials from the next source address, the remote addresses of the other ip family are skipped
+// by the dialer
+func (d *sourceDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if len(d.addrs) == 0 {
+		return d.Dialer.DialContext(ctx, network, addr)
+	}
+	ip := d.addrs[(d.next.Add(1)-1)%uint32(len(d.addrs))]
+	dialer := d.Dialer
+	if strings.HasPrefix(network, "udp") {
+		dialer.LocalAddr = &net.UDPAddr{IP: ip}
+	} else {
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// isPortsExhausted returns whether the error is the exhaustion of the ephemeral ports, which fails the connect
+// with EADDRNOTAVAIL or the bind of a source address with EADDRINUSE
+func isPortsExhausted(err error) bool {
+	return errors.Is(err, syscall.EADDRNOTAVAIL) || errors.Is(err, syscall.EADDRINUSE)
+}
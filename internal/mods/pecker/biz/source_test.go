@@ -0,0 +1,67 @@
+package biz
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceDialer(t *testing.T) {
+	var mu sync.Mutex
+	var sources []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		mu.Lock()
+		sources = append(sources, host)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	// the addresses of the task take the place of the ones of the config
+	cases := []struct {
+		conf   *conf.WorkerStressConf
+		r      *Requester
+		expect []string
+	}{
+		{conf: &conf.WorkerStressConf{}, r: &Requester{}, expect: []string{"127.0.0.1", "127.0.0.1", "127.0.0.1", "127.0.0.1"}},
+		{conf: &conf.WorkerStressConf{SourceAddresses: []string{"127.0.0.2"}}, r: &Requester{}, expect: []string{"127.0.0.2", "127.0.0.2", "127.0.0.2", "127.0.0.2"}},
+		{conf: &conf.WorkerStressConf{SourceAddresses: []string{"127.0.0.2"}}, r: &Requester{SourceAddresses: "127.0.0.3, 127.0.0.4"}, expect: []string{"127.0.0.3", "127.0.0.4", "127.0.0.3", "127.0.0.4"}},
+	}
+	for i, c := range cases {
+		for _, fastHttp := range []bool{false, true} {
+			sources = nil
+			c.conf.ErrorCutLength = 100
+			c.r.Method, c.r.Url, c.r.Timeout, c.r.DisableKeepAlive, c.r.FastHttp = http.MethodGet, srv.URL, 3, true, fastHttp
+			e, err := newProtocolExecutor(context.Background(), c.conf, c.r)
+			assert.NoError(t, err)
+			for range c.expect {
+				assert.Empty(t, e.Execute(context.Background()).Err)
+			}
+			e.Teardown(context.Background())
+			assert.Equal(t, c.expect, sources, "%d %v", i, fastHttp)
+		}
+	}
+
+	// the addresses not assigned to the pecker fail the setup
+	for _, addrs := range []string{"192.0.2.1", "gopeck"} {
+		_, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{}, &Requester{Url: srv.URL, SourceAddresses: addrs})
+		assert.Error(t, err, addrs)
+	}
+}
+
+func TestPortsExhaustedError(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.EADDRNOTAVAIL, syscall.EADDRINUSE} {
+		err := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", errno)}
+		assert.Equal(t, "dial: "+portsExhausted, cutError(err, 100))
+		assert.Equal(t, "dial: "+portsExhausted, socketErrorClass("dial", err, 100))
+	}
+	assert.NotEqual(t, "dial: "+portsExhausted, cutError(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, 100))
+}
@@ -0,0 +1,140 @@
+package biz
+
+import (
+	"context"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/atomicx"
+	"github.com/peckfly/gopeck/pkg/log/logc"
+	"go.uber.org/zap"
+	"math"
+	"sync"
+	"time"
+)
+
+// stageAdjustInterval is the interval of adjusting the virtual users to the target of the stage
+const stageAdjustInterval = 100 * time.Millisecond
+
+type (
+	// loadStage is a stage of the load profile, the target moves linearly from the from target at the
+	// start to the to target at the end
+	loadStage struct {
+		start time.Duration
+		end   time.Duration
+		from  float64
+		to    float64
+	}
+
+	// loadProfile is the stages of stage mode in order
+	loadProfile []loadStage
+)
+
+// newLoadProfile builds the load profile by the stage durations and the nums of the node, a linear stage
+// starts from the target of the previous stage, or zero for the first stage
+func newLoadProfile(r *Requester) loadProfile {
+	profile := make(loadProfile, 0, len(r.StageDurations))
+	var start time.Duration
+	var prev float64
+	for i, d := range r.StageDurations {
+		var target float64
+		if i < len(r.Nums) {
+			target = float64(r.Nums[i])
+		}
+		from := prev
+		if i < len(r.StageTransitions) && enums.StageTransition(r.StageTransitions[i]) == enums.InstantTransition {
+			from = target
+		}
+		end := start + time.Duration(d)*time.Second
+		profile = append(profile, loadStage{start: start, end: end, from: from, to: target})
+		start, prev = end, target
+	}
+	return profile
+}
+
+// duration returns the total duration of the stages
+func (p loadProfile) duration() time.Duration {
+	if len(p) == 0 {
+		return 0
+	}
+	return p[len(p)-1].end
+}
+
+// interval returns the index of the stage at the elapsed duration, the last stage lasts after its end
+func (p loadProfile) interval(elapsed time.Duration) int {
+	for i, stage := range p {
+		if elapsed < stage.end {
+			return i
+		}
+	}
+	return max(len(p)-1, 0)
+}
+
+// target returns the rps or concurrency at the elapsed duration
+func (p loadProfile) target(elapsed time.Duration) float64 {
+	if len(p) == 0 {
+		return 0
+	}
+	stage := p[p.interval(elapsed)]
+	if elapsed >= stage.end {
+		return stage.to
+	}
+	return stage.from + (stage.to-stage.from)*stage.progress(elapsed)
+}
+
+// hits returns the integral of the target in the elapsed duration
+func (p loadProfile) hits(t time.Duration) float64 {
+	var total float64
+	for _, stage := range p {
+		if t <= stage.start {
+			break
+		}
+		x := (min(t, stage.end) - stage.start).Seconds()
+		total += stage.from*x + (stage.to-stage.from)*x*x/(2*(stage.end-stage.start).Seconds())
+	}
+	return total
+}
+
+// progress returns the ratio of the elapsed duration in the stage
+func (s loadStage) progress(elapsed time.Duration) float64 {
+	if s.end <= s.start {
+		return 1
+	}
+	return float64(elapsed-s.start) / float64(s.end-s.start)
+}
+
+// runStageConcurrencyRequest keeps the running virtual users at the target of the stages, the virtual
+// users are started when the target rises, and the latest started ones quit after their current
+// iteration when the target falls
+func (b *RequesterUsecase) runStageConcurrencyRequest(ctx context.Context, r *Requester) {
+	var wg sync.WaitGroup
+	began := time.Now()
+	du := r.profile.duration()
+	var users []*atomicx.AtomicBool
+	for {
+		elapsed := time.Since(began)
+		if elapsed > du {
+			break
+		}
+		if stops[r.TaskId].True() {
+			logc.Info(ctx, "stop request got signal", zap.Uint64("task_id", r.TaskId))
+			break
+		}
+		target := int(math.Round(r.profile.target(elapsed)))
+		for len(users) < target {
+			quit := atomicx.ForAtomicBool(false)
+			users = append(users, quit)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b.runVirtualUser(ctx, r, began, du, quit)
+			}()
+		}
+		for len(users) > target {
+			users[len(users)-1].Set(true)
+			users = users[:len(users)-1]
+		}
+		time.Sleep(stageAdjustInterval)
+	}
+	wg.Wait()
+	elapsed := time.Since(began)
+	logc.Info(ctx, "run request stage concurrency mode cost:", zap.Uint64("task_id", r.TaskId), zap.Duration("cost", elapsed))
+}
@@ -0,0 +1,65 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+// rampSoakSpikeDown ramps up to 100 in 10s, soaks for 20s, spikes to 300 for 5s and ramps down to 0 in 10s
+var rampSoakSpikeDown = &Requester{
+	StressMode:       int32(enums.Stage),
+	Nums:             []int32{100, 100, 300, 0},
+	StageDurations:   []int32{10, 20, 5, 10},
+	StageTransitions: []int32{int32(enums.LinearTransition), int32(enums.LinearTransition), int32(enums.InstantTransition), 0},
+}
+
+func TestLoadProfileTarget(t *testing.T) {
+	p := newLoadProfile(rampSoakSpikeDown)
+	assert.Equal(t, 45*time.Second, p.duration())
+	tests := []struct {
+		elapsed  time.Duration
+		interval int
+		target   float64
+	}{
+		{elapsed: 0, interval: 0, target: 0},
+		{elapsed: 5 * time.Second, interval: 0, target: 50},
+		{elapsed: 10 * time.Second, interval: 1, target: 100},
+		{elapsed: 29 * time.Second, interval: 1, target: 100},
+		{elapsed: 30 * time.Second, interval: 2, target: 300},
+		{elapsed: 35 * time.Second, interval: 3, target: 300},
+		{elapsed: 40 * time.Second, interval: 3, target: 150},
+		{elapsed: 50 * time.Second, interval: 3, target: 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.interval, p.interval(tt.elapsed), tt.elapsed)
+		assert.InDelta(t, tt.target, p.target(tt.elapsed), 1e-9, tt.elapsed)
+	}
+}
+
+func TestLoadProfileHits(t *testing.T) {
+	p := newLoadProfile(rampSoakSpikeDown)
+	assert.InDelta(t, 500, p.hits(10*time.Second), 1e-9)
+	assert.InDelta(t, 500+2000, p.hits(30*time.Second), 1e-9)
+	assert.InDelta(t, 500+2000+1500+1500, p.hits(45*time.Second), 1e-9)
+	assert.InDelta(t, 500+2000+1500+1500, p.hits(time.Minute), 1e-9)
+}
+
+func TestStagePacer(t *testing.T) {
+	r := *rampSoakSpikeDown
+	r.profile = newLoadProfile(&r)
+	pacer := newPacer(&r)
+	assert.IsType(t, StagePacer{}, pacer)
+	assert.InDelta(t, 5500, simulateHits(pacer, time.Minute), 1)
+
+	// a stage of zero target sends nothing
+	idle := &Requester{StressMode: int32(enums.Stage), Nums: []int32{0, 10}, StageDurations: []int32{5, 5}, StageTransitions: []int32{2, 2}}
+	idle.profile = newLoadProfile(idle)
+	wait, stop := newPacer(idle).Pace(0, 0)
+	assert.False(t, stop)
+	assert.InDelta(t, float64(5*time.Second+100*time.Millisecond), float64(wait), float64(time.Millisecond))
+	_, stop = newPacer(idle).Pace(10*time.Second, 50)
+	assert.True(t, stop)
+}
@@ -4,14 +4,151 @@ import (
 	"context"
 	"github.com/panjf2000/ants/v2"
 	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
 	"github.com/peckfly/gopeck/pkg/log"
 	"github.com/peckfly/gopeck/pkg/log/logc"
 	"go.uber.org/zap"
 	"math"
+	"sync"
+	"time"
 )
 
 const KST int = 3
 
+const (
+	// collectorFlushInterval is the interval of flushing the aggregates of the idle workers
+	collectorFlushInterval = 250 * time.Millisecond
+	// collectorFlushGrace is how long an aggregate is kept after its second, it is far less than the KST
+	// seconds kept by stat, so that a second is never evicted by stat before all its aggregates arrive
+	collectorFlushGrace = 500 * time.Millisecond
+)
+
+type (
+	// collector aggregates the results of one worker by the second, the worker hands the aggregate of a
+	// second over to stat when it moves to the next second, and the flusher of the task hands it over
+	// when the worker is idle or blocked after the second, so the workers share nothing per request
+	collector struct {
+		r           *Requester
+		maxDuration time.Duration
+		mu          sync.Mutex
+		cur         *repo.Aggregate
+	}
+
+	// collectors are the collectors of the running workers of a task
+	collectors struct {
+		mu  sync.Mutex
+		set map[*collector]struct{}
+	}
+)
+
+func newCollectors() *collectors {
+	return &collectors{set: make(map[*collector]struct{})}
+}
+
+// newCollector returns a collector registered to the flusher of the task, it must be closed when the worker exits
+func (b *RequesterUsecase) newCollector(r *Requester) *collector {
+	c := &collector{r: r, maxDuration: time.Duration(b.conf.MaxTimeoutSecond) * time.Second}
+	if r.collectors != nil {
+		r.collectors.mu.Lock()
+		r.collectors.set[c] = struct{}{}
+		r.collectors.mu.Unlock()
+	}
+	return c
+}
+
+// add adds the result to the aggregate of its second
+func (c *collector) add(result *repo.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ar := c.at(result.TimeStamp)
+	ar.Add(result)
+	if result.Stop {
+		ar.Stop = true
+	}
+}
+
+// skip counts a request skipped at the second
+func (c *collector) skip(timestamp int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.at(timestamp).SkippedNum++
+}
+
+// at returns the aggregate of the second, the aggregate of the previous second is handed over
+func (c *collector) at(timestamp int64) *repo.Aggregate {
+	if c.cur != nil && c.cur.Timestamp != timestamp {
+		c.handOver()
+	}
+	if c.cur == nil {
+		c.cur = repo.NewAggeRate()
+		c.cur.Timestamp = timestamp
+		c.cur.MaxDuration = c.maxDuration
+		c.cur.Node = c.r.Addr
+	}
+	return c.cur
+}
+
+// handOver hands the aggregate of the current second over to stat
+func (c *collector) handOver() {
+	if c.cur != nil {
+		c.r.aggregates <- c.cur
+		c.cur = nil
+	}
+}
+
+// flush hands the aggregate of the current second over to stat
+func (c *collector) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handOver()
+}
+
+// flushBefore hands the aggregate over if its second and the grace are over at the time
+func (c *collector) flushBefore(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cur != nil && t.After(time.Unix(c.cur.Timestamp+1, 0).Add(collectorFlushGrace)) {
+		c.handOver()
+	}
+}
+
+// close flushes the collector and removes it from the flusher, it must be called when the worker exits
+func (c *collector) close() {
+	if c.r.collectors != nil {
+		c.r.collectors.mu.Lock()
+		delete(c.r.collectors.set, c)
+		c.r.collectors.mu.Unlock()
+	}
+	c.flush()
+}
+
+// flush hands the aggregates of the idle collectors over to stat every collectorFlushInterval until the
+// returned stop is called, stop waits for the flusher to exit, so the aggregates channel can be closed
+func (cs *collectors) flush() (stop func()) {
+	quit, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(collectorFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case t := <-ticker.C:
+				cs.mu.Lock()
+				for c := range cs.set {
+					c.flushBefore(t)
+				}
+				cs.mu.Unlock()
+			}
+		}
+	}()
+	return func() {
+		close(quit)
+		<-exited
+	}
+}
+
 func (b *RequesterUsecase) report(ctx context.Context, r *Requester) error {
 	var err error
 	r.poolFunc, err = ants.NewPoolWithFunc(b.conf.ReportGoroutineNum, func(data interface{}) {
@@ -36,10 +173,13 @@ func (b *RequesterUsecase) report(ctx context.Context, r *Requester) error {
 		}
 		tars := make(map[int64]*repo.Aggregate, KST)
 		stop := false
-		for result := range r.results {
-			timestamp := result.TimeStamp
+		for ar := range r.aggregates {
+			timestamp := ar.Timestamp
 			costSecond := timestamp - r.StartTime
 			interval := min(int(intervalsLen)-1, int(costSecond)/int(r.StepIntervalTime))
+			if r.StressMode == int32(enums.Stage) {
+				interval = min(intervalsLen-1, r.profile.interval(time.Duration(costSecond)*time.Second))
+			}
 
 			var car *repo.Aggregate
 			var ok bool
@@ -49,13 +189,8 @@ func (b *RequesterUsecase) report(ctx context.Context, r *Requester) error {
 				car.Interval = interval
 				tars[timestamp] = car
 			}
-			car.TotalNum++
-			car.TotalResponseContentLength += result.ResponseContentLength
-			car.DurationMap[int32(result.Duration.Milliseconds())]++
-			car.StatusMap[int32(result.StatusCode)]++
-			car.ErrorMap[result.Err]++
-			car.BodyCheckResultMap[result.BodyCheckResult]++
-			if result.Stop {
+			car.Merge(ar)
+			if ar.Stop {
 				car.Stop = true
 				stop = true
 			}
@@ -76,12 +211,7 @@ func (b *RequesterUsecase) report(ctx context.Context, r *Requester) error {
 			}
 
 			ags[interval].Interval = interval
-			ags[interval].TotalNum++
-			ags[interval].TotalResponseContentLength += result.ResponseContentLength
-			ags[interval].DurationMap[int32(result.Duration.Milliseconds())]++
-			ags[interval].StatusMap[int32(result.StatusCode)]++
-			ags[interval].ErrorMap[result.Err]++
-			ags[interval].BodyCheckResultMap[result.BodyCheckResult]++
+			ags[interval].Merge(ar)
 		}
 		for _, bar := range tars {
 			err = r.poolFunc.Invoke(bar)
@@ -89,7 +219,7 @@ func (b *RequesterUsecase) report(ctx context.Context, r *Requester) error {
 				logc.Error(ctx, "failed to push result", zap.Error(err))
 			}
 		}
-		logc.Info(ctx, "result channel is closed, done report")
+		logc.Info(ctx, "aggregate channel is closed, done report")
 		if err != nil {
 			logc.Error(ctx, "failed to push stop result", zap.Error(err))
 		}
@@ -104,6 +234,36 @@ func (b *RequesterUsecase) report(ctx context.Context, r *Requester) error {
 				StatusMap:                  agr.StatusMap,
 				ErrorMap:                   agr.ErrorMap,
 				BodyCheckResultMap:         agr.BodyCheckResultMap,
+				HandshakeDurationMap:       agr.HandshakeDurationMap,
+				MessageDurationMap:         agr.MessageDurationMap,
+				DisconnectNum:              agr.DisconnectNum,
+				FirstByteDurationMap:       agr.FirstByteDurationMap,
+				FirstEventDurationMap:      agr.FirstEventDurationMap,
+				EventGapDurationMap:        agr.EventGapDurationMap,
+				StreamDurationMap:          agr.StreamDurationMap,
+				ConnectDurationMap:         agr.ConnectDurationMap,
+				WriteDurationMap:           agr.WriteDurationMap,
+				ReadDurationMap:            agr.ReadDurationMap,
+				DnsDurationMap:             agr.DnsDurationMap,
+				TlsDurationMap:             agr.TlsDurationMap,
+				WaitDurationMap:            agr.WaitDurationMap,
+				TransferDurationMap:        agr.TransferDurationMap,
+				CommandDurationMap:         agr.CommandDurationMap,
+				StepDurationMap:            agr.StepDurationMap,
+				StepErrorMap:               agr.StepErrorMap,
+				ResponseDurationMap:        agr.ResponseDurationMap,
+				LateNum:                    agr.LateNum,
+				SkippedNum:                 agr.SkippedNum,
+				TotalRequestBytes:          agr.TotalRequestBytes,
+				TotalResponseBytes:         agr.TotalResponseBytes,
+				ResponseSizeMap:            agr.ResponseSizeMap,
+				TlsHandshakeNum:            agr.TlsHandshakeNum,
+				TlsResumedNum:              agr.TlsResumedNum,
+				TlsVersionMap:              agr.TlsVersionMap,
+				TlsCipherMap:               agr.TlsCipherMap,
+				NewConnMap:                 agr.NewConnMap,
+				ReusedConnMap:              agr.ReusedConnMap,
+				PeakOpenConnMap:            agr.PeakOpenConnMap,
 				Stop:                       i == len(ags)-1 && stop,
 			})
 		}
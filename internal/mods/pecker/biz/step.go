@@ -5,22 +5,17 @@ import (
 	"github.com/peckfly/gopeck/pkg/log/logc"
 	"github.com/peckfly/gopeck/pkg/numx"
 	"go.uber.org/zap"
-	"net/http"
 	"sync"
 	"time"
 )
 
-func (b *RequesterUsecase) runStepRpsRequest(ctx context.Context, client *http.Client, r *Requester) {
+func (b *RequesterUsecase) runStepRpsRequest(ctx context.Context, r *Requester) {
 	logc.Info(ctx, "start run step rps request", zap.Uint64("task_id", r.TaskId), zap.Int32("stress_time", r.StressTime))
 	pacer := ConstantPacer{int(r.Nums[0]), time.Second}
 	began, count := time.Now(), uint64(0)
 	start := time.Now()
-	taskChan := make(chan struct{})
-	var wg sync.WaitGroup
+	d := b.newDispatcher(ctx, r)
 	du := time.Duration(r.StressTime) * time.Second
-	costGoroutineNums := 1
-	wg.Add(1)
-	go b.requestFromChan(ctx, taskChan, &wg, client, r)
 	intervalsLen := len(r.Nums)
 	cm := make([]bool, intervalsLen)
 	cm[0] = true
@@ -30,6 +25,7 @@ func (b *RequesterUsecase) runStepRpsRequest(ctx context.Context, client *http.C
 		if stop {
 			break
 		}
+		intended := began.Add(scheduledAt(pacer, 0, elapsed, count, wait))
 		cost := time.Since(start)
 		if cost > du {
 			break
@@ -47,27 +43,15 @@ func (b *RequesterUsecase) runStepRpsRequest(ctx context.Context, client *http.C
 			logc.Info(ctx, "stop request got signal", zap.Uint64("task_id", r.TaskId))
 			break
 		}
-		select {
-		case taskChan <- struct{}{}:
-			count++
-			continue
-		default:
-			wg.Add(1)
-			costGoroutineNums++
-			go b.requestFromChan(ctx, taskChan, &wg, client, r)
-		}
-		select {
-		case taskChan <- struct{}{}:
-			count++
-		}
+		d.dispatch(intended)
+		count++
 	}
-	close(taskChan)
-	wg.Wait()
+	d.close()
 	elapsed := time.Since(began)
-	logc.Info(ctx, "run request rps mode cost:", zap.Uint64("task_id", r.TaskId), zap.Duration("cost", elapsed), zap.Int("cost_goroutine_num", costGoroutineNums))
+	logc.Info(ctx, "run request rps mode cost:", zap.Uint64("task_id", r.TaskId), zap.Duration("cost", elapsed), zap.Int("cost_goroutine_num", d.workers))
 }
 
-func (b *RequesterUsecase) runStepConcurrencyRequest(ctx context.Context, client *http.Client, r *Requester) {
+func (b *RequesterUsecase) runStepConcurrencyRequest(ctx context.Context, r *Requester) {
 	var wg sync.WaitGroup
 	began := time.Now()
 	du := time.Duration(r.StressTime) * time.Second
@@ -77,24 +61,14 @@ func (b *RequesterUsecase) runStepConcurrencyRequest(ctx context.Context, client
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				for {
-					elapsed := time.Since(began)
-					if elapsed > du {
-						break
-					}
-					if stops[r.TaskId].True() {
-						logc.Info(ctx, "stop request got signal", zap.Uint64("task_id", r.TaskId))
-						break
-					}
-					b.goRequest(ctx, client, r)
-				}
+				b.runVirtualUser(ctx, r, began, du, nil)
 			}()
 		}
 	}
 	preNum := 0
 	for i := 0; i < int(intervalsLen); i++ {
 		run(int(r.Nums[i]) - preNum)
-		time.Sleep(time.Duration(r.StepIntervalTime) * time.Minute)
+		time.Sleep(time.Duration(r.StepIntervalTime) * time.Second)
 		preNum = int(r.Nums[i])
 	}
 	wg.Wait()
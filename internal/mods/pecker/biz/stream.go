@@ -0,0 +1,99 @@
+package biz
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"io"
+	"time"
+)
+
+// streamReadSize is the buffer size of reading a chunked stream
+const streamReadSize = 32 * 1024
+
+var (
+	sseDataField  = []byte("data")
+	sseDataPrefix = []byte("data:")
+)
+
+// streamResult is the timing of a streaming response, event and end times are offsets from now()
+type streamResult struct {
+	events []time.Duration
+	end    time.Duration
+	size   int64
+	body   []byte
+}
+
+// readStream reads the response body as a stream until it ends, an event is a dispatched server-sent event
+// or a received chunk according to the stream type. The body is kept only if keepBody is set.
+func readStream(body io.Reader, streamType enums.ResponseStreamType, keepBody bool) (*streamResult, error) {
+	var err error
+	stream := &streamResult{}
+	if streamType == enums.SseStream {
+		err = readSseStream(body, stream, keepBody)
+	} else {
+		err = readChunkedStream(body, stream, keepBody)
+	}
+	stream.end = now()
+	return stream, err
+}
+
+// readSseStream reads server-sent events separated by blank lines, only the events with data
+// are dispatched, the comment lines used as keep-alive are ignored
+func readSseStream(body io.Reader, stream *streamResult, keepBody bool) error {
+	reader := bufio.NewReader(body)
+	hasData, continued := false, false
+	for {
+		line, err := reader.ReadSlice('\n')
+		stream.size += int64(len(line))
+		if keepBody {
+			stream.body = append(stream.body, line...)
+		}
+		if !continued {
+			trimmed := bytes.TrimRight(line, "\r\n")
+			if len(trimmed) == 0 && len(line) > 0 {
+				if hasData {
+					stream.events = append(stream.events, now())
+				}
+				hasData = false
+			} else if bytes.Equal(trimmed, sseDataField) || bytes.HasPrefix(trimmed, sseDataPrefix) {
+				hasData = true
+			}
+		}
+		// a line longer than the buffer is read in pieces, the rest pieces are not line starts
+		continued = errors.Is(err, bufio.ErrBufferFull)
+		if continued {
+			continue
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// readChunkedStream reads the body as it arrives, every read with data counts as an event
+func readChunkedStream(body io.Reader, stream *streamResult, keepBody bool) error {
+	bufp := bodyBufferPool.Get().(*[]byte)
+	defer bodyBufferPool.Put(bufp)
+	buf := *bufp
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			stream.events = append(stream.events, now())
+			stream.size += int64(n)
+			if keepBody {
+				stream.body = append(stream.body, buf[:n]...)
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package biz
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSseStream(t *testing.T) {
+	long := strings.Repeat("x", 3*4096)
+	tests := []struct {
+		name   string
+		body   string
+		events int
+	}{
+		{name: "blank line dispatches", body: "data: a\n\ndata: b\n\n", events: 2},
+		{name: "multi-line data is one event", body: "data: a\ndata: b\n\n", events: 1},
+		{name: "data field without value", body: "data\n\n", events: 1},
+		{name: "no dispatch without blank line", body: "data: a\n\ndata: b\n", events: 1},
+		{name: "comment keep-alives", body: ": ping\n\n: ping\n\ndata: a\n\n:ping\n\n", events: 1},
+		{name: "events without data", body: "event: open\nid: 1\n\nretry: 10\n\n", events: 0},
+		{name: "not a data field", body: "database: a\n\n", events: 0},
+		{name: "crlf", body: "data: a\r\n\r\ndata: b\r\n\r\n: ping\r\n\r\n", events: 2},
+		// the pieces of a long line are not taken as line starts, even if they look like a blank line or data
+		{name: "line longer than the buffer", body: "data: " + long + "\n\n", events: 1},
+		{name: "long comment", body: ": " + long[:4096-2] + "data: a\n\n", events: 0},
+		{name: "line end after the buffer is not a blank line", body: "data: " + long[:4096-6] + "\ndata: b\n\n", events: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the body arrives byte by byte to exercise the partial reads too
+			for _, body := range []io.Reader{strings.NewReader(tt.body), iotest.OneByteReader(strings.NewReader(tt.body))} {
+				stream, err := readStream(body, enums.SseStream, true)
+				assert.NoError(t, err)
+				assert.Len(t, stream.events, tt.events)
+				assert.Equal(t, int64(len(tt.body)), stream.size)
+				assert.Equal(t, tt.body, string(stream.body))
+			}
+		})
+	}
+	// the long lines are read in pieces by the default buffer of the reader
+	assert.Greater(t, len(long), bufio.NewReader(nil).Size())
+
+	stream, err := readStream(iotest.TimeoutReader(strings.NewReader("data: a\n\ndata: b\n\n")), enums.SseStream, false)
+	assert.ErrorIs(t, err, iotest.ErrTimeout)
+	assert.Nil(t, stream.body)
+}
+
+func TestReadChunkedStream(t *testing.T) {
+	body := strings.Repeat("x", streamReadSize+10)
+	stream, err := readStream(iotest.HalfReader(strings.NewReader(body)), enums.ChunkedStream, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(body)), stream.size)
+	assert.Len(t, stream.events, 3)
+	assert.Nil(t, stream.body)
+	for i := 1; i < len(stream.events); i++ {
+		assert.GreaterOrEqual(t, stream.events[i], stream.events[i-1])
+	}
+	assert.GreaterOrEqual(t, stream.end, stream.events[len(stream.events)-1])
+
+	stream, err = readStream(strings.NewReader(body), enums.ChunkedStream, true)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(stream.body))
+}
@@ -0,0 +1,86 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"github.com/peckfly/gopeck/pkg/netx"
+	"io"
+	"net/http"
+)
+
+type (
+	// requestTemplate is the http request parsed once before the stress, the requests are shallow copies
+	// of the prototype with their own context and body reader, the url and the headers are shared and
+	// must not be modified
+	requestTemplate struct {
+		proto *http.Request
+		body  []byte
+		// size is the bytes of the request written in http/1.1, it estimates the request bytes when
+		// they are not counted on the connection
+		size int64
+	}
+
+	// templateBody is the body reader of a request from the template
+	templateBody struct {
+		bytes.Reader
+	}
+)
+
+func newRequestTemplate(method string, urlStr string, headers map[string]string, query map[string]string, body string) (*requestTemplate, error) {
+	proto, err := constructRequest(method, urlStr, headers, query, body)
+	if err != nil {
+		return nil, err
+	}
+	w := &countingWriter{}
+	if err = proto.Write(w); err != nil {
+		return nil, err
+	}
+	t := &requestTemplate{proto: proto, body: []byte(body), size: w.n}
+	if len(t.body) == 0 {
+		proto.Body, proto.GetBody = http.NoBody, nil
+		return t, nil
+	}
+	proto.GetBody = func() (io.ReadCloser, error) {
+		return t.newBody(), nil
+	}
+	return t, nil
+}
+
+// request returns a new request of the template with the context
+func (t *requestTemplate) request(ctx context.Context) *http.Request {
+	req := t.proto.WithContext(ctx)
+	if len(t.body) > 0 {
+		req.Body = t.newBody()
+	}
+	return req
+}
+
+func (t *requestTemplate) newBody() io.ReadCloser {
+	b := &templateBody{}
+	b.Reset(t.body)
+	return b
+}
+
+func (b *templateBody) Close() error {
+	return nil
+}
+
+// newRequestTemplates builds the template of every dynamic param, or the template of the static request
+func newRequestTemplates(r *Requester) ([]*requestTemplate, error) {
+	if len(r.DynamicParams) == 0 {
+		t, err := newRequestTemplate(r.Method, r.Url, r.Headers, netx.ParseQuery(r.Query), r.Body)
+		if err != nil {
+			return nil, err
+		}
+		return []*requestTemplate{t}, nil
+	}
+	templates := make([]*requestTemplate, 0, len(r.DynamicParams))
+	for _, param := range r.DynamicParams {
+		t, err := newRequestTemplate(r.Method, r.Url, param.Headers, param.Query, param.Body)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
@@ -0,0 +1,84 @@
+package biz
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/peckfly/gopeck/pkg/netx"
+	"github.com/stretchr/testify/assert"
+)
+
+var templateRequester = &Requester{
+	Method:  http.MethodPost,
+	Url:     "http://127.0.0.1:8080/api/v1/users?source=peck",
+	Headers: map[string]string{"Content-Type": "application/json", "X-Trace": "gopeck"},
+	Query:   "page=1&size=20",
+	Body:    `{"name":"gopeck","tags":["stress","http"]}`,
+}
+
+func TestRequestTemplate(t *testing.T) {
+	r := templateRequester
+	templates, err := newRequestTemplates(r)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+	expect, err := constructRequest(r.Method, r.Url, r.Headers, netx.ParseQuery(r.Query), r.Body)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		req := templates[0].request(context.Background())
+		assert.Equal(t, expect.Method, req.Method)
+		assert.Equal(t, expect.URL.String(), req.URL.String())
+		assert.Equal(t, expect.Host, req.Host)
+		assert.Equal(t, expect.Header, req.Header)
+		assert.Equal(t, expect.ContentLength, req.ContentLength)
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, r.Body, string(body))
+		again, err := req.GetBody()
+		assert.NoError(t, err)
+		body, err = io.ReadAll(again)
+		assert.NoError(t, err)
+		assert.Equal(t, r.Body, string(body))
+	}
+
+	get, err := newRequestTemplate(http.MethodGet, r.Url, nil, nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, http.NoBody, get.request(context.Background()).Body)
+
+	dynamic := &Requester{Method: http.MethodGet, Url: r.Url, DynamicParams: []*DynamicParam{
+		{Query: map[string]string{"id": "1"}},
+		{Query: map[string]string{"id": "2"}, Headers: map[string]string{"X-Id": "2"}},
+	}}
+	templates, err = newRequestTemplates(dynamic)
+	assert.NoError(t, err)
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "id=2&source=peck", templates[1].request(context.Background()).URL.RawQuery)
+	assert.Equal(t, "2", templates[1].request(context.Background()).Header.Get("X-Id"))
+
+	_, err = newRequestTemplates(&Requester{Method: http.MethodGet, Url: "http://[::1"})
+	assert.Error(t, err)
+}
+
+// benchRequest keeps the requests of the benchmarks escaping like the requests sent by the client
+var benchRequest *http.Request
+
+// BenchmarkConstructRequest is the request of every call built from the task
+func BenchmarkConstructRequest(b *testing.B) {
+	r := templateRequester
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchRequest, _ = constructRequest(r.Method, r.Url, r.Headers, netx.ParseQuery(r.Query), r.Body)
+	}
+}
+
+// BenchmarkRequestTemplate is the request of every call copied from the template
+func BenchmarkRequestTemplate(b *testing.B) {
+	templates, _ := newRequestTemplates(templateRequester)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchRequest = templates[0].request(ctx)
+	}
+}
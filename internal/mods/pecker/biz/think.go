@@ -0,0 +1,56 @@
+package biz
+
+import (
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"math/rand"
+	"time"
+)
+
+// thinker decides how long a virtual user waits before its next iteration in concurrency mode,
+// the next iteration starts after the think time, and not earlier than the pacing time since
+// the previous iteration started
+type thinker struct {
+	thinkTimeType enums.ThinkTimeType
+	thinkTime     time.Duration
+	thinkTimeMax  time.Duration
+	pacingTime    time.Duration
+}
+
+// newThinker returns nil if the requester neither thinks nor paces
+func newThinker(r *Requester) *thinker {
+	t := &thinker{
+		thinkTimeType: enums.ThinkTimeType(r.ThinkTimeType),
+		thinkTime:     time.Duration(r.ThinkTime) * time.Millisecond,
+		thinkTimeMax:  time.Duration(r.ThinkTimeMax) * time.Millisecond,
+		pacingTime:    time.Duration(r.PacingTime) * time.Millisecond,
+	}
+	if t.thinkTimeType <= 0 && t.pacingTime <= 0 {
+		return nil
+	}
+	return t
+}
+
+// wait returns the wait time before the next iteration, cost is the duration of the previous iteration
+func (t *thinker) wait(cost time.Duration) time.Duration {
+	think := t.think()
+	if t.pacingTime > 0 {
+		return max(think, t.pacingTime-cost)
+	}
+	return think
+}
+
+// think samples the think time of the think time type
+func (t *thinker) think() time.Duration {
+	switch t.thinkTimeType {
+	case enums.ConstantThinkTime:
+		return t.thinkTime
+	case enums.UniformThinkTime:
+		if t.thinkTimeMax <= t.thinkTime {
+			return t.thinkTime
+		}
+		return t.thinkTime + time.Duration(rand.Int63n(int64(t.thinkTimeMax-t.thinkTime)))
+	case enums.ExponentialThinkTime:
+		return time.Duration(rand.ExpFloat64() * float64(t.thinkTime))
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package biz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThinker(t *testing.T) {
+	assert.Nil(t, newThinker(&Requester{}))
+
+	constant := newThinker(&Requester{ThinkTimeType: int32(enums.ConstantThinkTime), ThinkTime: 100})
+	assert.Equal(t, 100*time.Millisecond, constant.wait(time.Second))
+
+	uniform := newThinker(&Requester{ThinkTimeType: int32(enums.UniformThinkTime), ThinkTime: 100, ThinkTimeMax: 200})
+	for i := 0; i < 100; i++ {
+		wait := uniform.wait(0)
+		assert.GreaterOrEqual(t, wait, 100*time.Millisecond)
+		assert.Less(t, wait, 200*time.Millisecond)
+	}
+
+	exponential := newThinker(&Requester{ThinkTimeType: int32(enums.ExponentialThinkTime), ThinkTime: 100})
+	var total time.Duration
+	n := 10000
+	for i := 0; i < n; i++ {
+		wait := exponential.wait(0)
+		assert.GreaterOrEqual(t, wait, time.Duration(0))
+		total += wait
+	}
+	assert.InDelta(t, float64(100*time.Millisecond), float64(total)/float64(n), float64(10*time.Millisecond))
+
+	// the next iteration starts every pacing time, unless the iteration and think time exceed it
+	pacing := newThinker(&Requester{PacingTime: 1000})
+	assert.Equal(t, 700*time.Millisecond, pacing.wait(300*time.Millisecond))
+	assert.Equal(t, time.Duration(0), pacing.wait(1500*time.Millisecond))
+	pacingWithThink := newThinker(&Requester{ThinkTimeType: int32(enums.ConstantThinkTime), ThinkTime: 800, PacingTime: 1000})
+	assert.Equal(t, 800*time.Millisecond, pacingWithThink.wait(300*time.Millisecond))
+	assert.Equal(t, 700*time.Millisecond, newThinker(&Requester{ThinkTimeType: int32(enums.ConstantThinkTime), ThinkTime: 100, PacingTime: 1000}).wait(300*time.Millisecond))
+}
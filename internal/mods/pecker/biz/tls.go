@@ -0,0 +1,60 @@
+package biz
+
+import (
+	"crypto/tls"
+	"errors"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/pkg/tlsx"
+	"net"
+)
+
+// tlsVerifyErrorPrefix is the prefix of the errors of the certificate verification, so that they are
+// counted as one kind of error whatever the protocol is
+const tlsVerifyErrorPrefix = "tls certificate verification failed: "
+
+// tlsSessionCacheSize is the capacity of the session cache of a task, the sessions are cached by the server name
+const tlsSessionCacheSize = 64
+
+// newTlsConfig builds the client tls config of the tls settings of the task, the pem material is
+// delivered encrypted and decrypted here with the tls secret. The sessions are cached to be resumed like a
+// browser does unless the fresh handshake is forced, which disables the session tickets and the keep-alive too.
+func newTlsConfig(conf *conf.WorkerStressConf, r *Requester) (*tls.Config, error) {
+	config, err := tlsx.NewConfig(&tlsx.Options{
+		Secret:       conf.TlsSecret,
+		Verify:       r.TlsVerify,
+		Ca:           r.TlsCa,
+		Cert:         r.TlsCert,
+		Key:          r.TlsKey,
+		ServerName:   r.TlsServerName,
+		MinVersion:   r.TlsMinVersion,
+		MaxVersion:   r.TlsMaxVersion,
+		CipherSuites: r.TlsCipherSuites,
+		Alpn:         r.TlsAlpn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r.TlsFreshHandshake {
+		config.SessionTicketsDisabled = true
+	} else {
+		config.ClientSessionCache = tls.NewLRUClientSessionCache(tlsSessionCacheSize)
+	}
+	return config, nil
+}
+
+// tlsConnState returns the state of the tls connection, false if it is not a tls connection
+func tlsConnState(conn net.Conn) (tls.ConnectionState, bool) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		return tlsConn.ConnectionState(), true
+	}
+	return tls.ConnectionState{}, false
+}
+
+// tlsVerifyError returns the reason of the certificate verification failure of the error if it is
+func tlsVerifyError(err error) (string, bool) {
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) {
+		return verifyErr.Err.Error(), true
+	}
+	return "", false
+}
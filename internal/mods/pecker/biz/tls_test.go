@@ -0,0 +1,157 @@
+package biz
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/pkg/tlsx"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCert is a certificate signed by the parent, the root is signed by itself
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem string
+	keyPem  string
+}
+
+func newTestCert(t *testing.T, parent *testCert, template *x509.Certificate) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template.NotBefore, template.NotAfter = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPem:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
+
+func TestMutualTls(t *testing.T) {
+	ca := newTestCert(t, nil, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gopeck ca"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	})
+	server := newTestCert(t, ca, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		DNSNames:     []string{"gopeck.io"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := newTestCert(t, ca, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "gopeck client"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	pair, err := tls.X509KeyPair([]byte(server.certPem), []byte(server.keyPem))
+	assert.NoError(t, err)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{pair}, ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	// the material is delivered encrypted with the secret shared by the admin and the peckers
+	c := &conf.WorkerStressConf{ErrorCutLength: 100, TlsSecret: "gopeck-secret"}
+	encrypt := func(pem string) string {
+		encrypted, err := tlsx.EncryptPem(pem, c.TlsSecret)
+		assert.NoError(t, err)
+		return encrypted
+	}
+	caPem, certPem, keyPem := encrypt(ca.certPem), encrypt(client.certPem), encrypt(client.keyPem)
+	for _, fastHttp := range []bool{false, true} {
+		execute := func(r *Requester) string {
+			r.Method, r.Url, r.Timeout, r.FastHttp = http.MethodGet, srv.URL, 3, fastHttp
+			e, err := newProtocolExecutor(context.Background(), c, r)
+			assert.NoError(t, err)
+			defer e.Teardown(context.Background())
+			return e.Execute(context.Background()).Err
+		}
+		assert.Empty(t, execute(&Requester{TlsCa: caPem, TlsCert: certPem, TlsKey: keyPem}), fastHttp)
+		assert.Empty(t, execute(&Requester{TlsCa: caPem, TlsCert: certPem, TlsKey: keyPem, TlsServerName: "gopeck.io"}), fastHttp)
+		// the server refuses the client without certificate
+		assert.NotEmpty(t, execute(&Requester{TlsCa: caPem}), fastHttp)
+		// the verification failures are one kind of error
+		err := execute(&Requester{TlsVerify: true, TlsCert: certPem, TlsKey: keyPem})
+		assert.True(t, strings.HasPrefix(err, tlsVerifyErrorPrefix), err)
+		err = execute(&Requester{TlsCa: caPem, TlsCert: certPem, TlsKey: keyPem, TlsServerName: "peck.io"})
+		assert.True(t, strings.HasPrefix(err, tlsVerifyErrorPrefix), err)
+	}
+
+	_, err = newProtocolExecutor(context.Background(), c, &Requester{Url: srv.URL, TlsCert: certPem})
+	assert.Error(t, err)
+	// the plain material is not accepted, nor the material without the secret of the pecker
+	_, err = newProtocolExecutor(context.Background(), c, &Requester{Url: srv.URL, TlsCa: ca.certPem})
+	assert.ErrorIs(t, err, tlsx.ErrPlainPem)
+	_, err = newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, &Requester{Url: srv.URL, TlsCa: caPem})
+	assert.ErrorIs(t, err, tlsx.ErrNoSecret)
+}
+
+func TestTlsResumption(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("gopeck"))
+	}))
+	defer srv.Close()
+	cases := []struct {
+		r         *Requester
+		handshake []bool
+		resumed   []bool
+	}{
+		// the connection is reused without handshake
+		{r: &Requester{}, handshake: []bool{true, false, false}, resumed: []bool{false, false, false}},
+		// the session is resumed by the new connections
+		{r: &Requester{DisableKeepAlive: true}, handshake: []bool{true, true, true}, resumed: []bool{false, true, true}},
+		{r: &Requester{TlsFreshHandshake: true}, handshake: []bool{true, true, true}, resumed: []bool{false, false, false}},
+	}
+	for i, c := range cases {
+		c.r.Method, c.r.Url, c.r.Timeout = http.MethodGet, srv.URL, 3
+		e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, c.r)
+		assert.NoError(t, err)
+		for j := range c.handshake {
+			result := e.Execute(context.Background())
+			assert.Empty(t, result.Err)
+			assert.Equal(t, c.handshake[j], result.TlsHandshake, i, j)
+			assert.Equal(t, c.resumed[j], result.TlsResumed, i, j)
+			if result.TlsHandshake {
+				assert.Equal(t, "TLS 1.3", result.TlsVersion)
+				assert.NotEmpty(t, result.TlsCipher)
+				assert.Positive(t, result.TlsDuration)
+			}
+		}
+		e.Teardown(context.Background())
+	}
+}
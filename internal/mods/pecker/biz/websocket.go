@@ -0,0 +1,249 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/mods/common/repo"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// websocketExecutor runs one virtual user session per execution: connect, send the scripted messages
+	// and wait for their replies, then hold the connection open for the hold time
+	websocketExecutor struct {
+		conf     *conf.WorkerStressConf
+		r        *Requester
+		dialer   *websocket.Dialer
+		messages []wsMessage
+		seq      atomic.Uint64
+	}
+
+	// wsMessage is a scripted message, object messages carry the correlation field
+	wsMessage struct {
+		text   []byte
+		object map[string]any
+	}
+)
+
+func newWebsocketExecutor(conf *conf.WorkerStressConf) ProtocolExecutor {
+	return &websocketExecutor{conf: conf}
+}
+
+func (e *websocketExecutor) Setup(ctx context.Context, r *Requester) error {
+	e.r = r
+	tlsConfig, err := newTlsConfig(e.conf, r)
+	if err != nil {
+		return err
+	}
+	dial, _, err := newDialer(e.conf, r, 0)
+	if err != nil {
+		return err
+	}
+	e.dialer = &websocket.Dialer{
+		NetDialContext:    dial,
+		HandshakeTimeout:  time.Duration(r.Timeout) * time.Second,
+		TLSClientConfig:   tlsConfig,
+		EnableCompression: !r.DisableCompression,
+	}
+	if len(r.Proxy) > 0 {
+		if proxyUrl, err := url.Parse(r.Proxy); err == nil {
+			e.dialer.Proxy = http.ProxyURL(proxyUrl)
+		}
+	}
+	messages, err := parseWsMessages(r.WsMessages)
+	if err != nil {
+		return err
+	}
+	e.messages = messages
+	return nil
+}
+
+func (e *websocketExecutor) Execute(ctx context.Context) *repo.Result {
+	r := e.r
+	headers := r.Headers
+	if len(r.DynamicParams) > 0 {
+		headers = r.DynamicParams[rand.Intn(len(r.DynamicParams))].Headers
+	}
+	header := make(http.Header, len(headers))
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+	result := &repo.Result{TimeStamp: time.Now().Unix(), StatusCode: noResponseStatus}
+	s := now()
+	conn, resp, err := e.dialer.DialContext(ctx, r.Url, header)
+	result.HandshakeDuration = now() - s
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		result.Err = cutError(err, e.conf.ErrorCutLength)
+		result.Duration = now() - s
+		return result
+	}
+	defer conn.Close()
+	timeout := time.Duration(r.Timeout) * time.Second
+	for _, message := range e.messages {
+		duration, err := e.roundTrip(conn, message, timeout)
+		if err != nil {
+			result.Err = cutError(err, e.conf.ErrorCutLength)
+			result.Disconnected = isWsDisconnect(err)
+			result.Duration = now() - s
+			return result
+		}
+		if duration > 0 {
+			result.MessageDurations = append(result.MessageDurations, duration)
+		}
+	}
+	result.Duration = now() - s
+	if err = e.hold(conn); err != nil {
+		result.Err = cutError(err, e.conf.ErrorCutLength)
+		result.Disconnected = true
+		return result
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return result
+}
+
+func (e *websocketExecutor) Teardown(ctx context.Context) {
+}
+
+// roundTrip sends the message and waits for its reply, the reply is matched by the correlation field if it is set,
+// otherwise the next received message is the reply. Zero duration means the message has no reply to wait.
+func (e *websocketExecutor) roundTrip(conn *websocket.Conn, message wsMessage, timeout time.Duration) (time.Duration, error) {
+	field := e.r.WsCorrelationField
+	payload := message.text
+	var id string
+	if len(field) > 0 {
+		if message.object == nil {
+			return 0, conn.WriteMessage(websocket.TextMessage, payload)
+		}
+		id = strconv.FormatUint(e.r.TaskId, 10) + "-" + strconv.FormatUint(e.seq.Add(1), 10)
+		object := make(map[string]any, len(message.object)+1)
+		for k, v := range message.object {
+			object[k] = v
+		}
+		object[field] = id
+		var err error
+		if payload, err = json.Marshal(object); err != nil {
+			return 0, err
+		}
+	}
+	start := now()
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+		return 0, err
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+	for {
+		_, reply, err := conn.ReadMessage()
+		if err != nil {
+			return 0, err
+		}
+		if len(field) == 0 || wsCorrelationId(reply, field) == id {
+			return now() - start, nil
+		}
+	}
+}
+
+// hold keeps the connection open for the hold time and drains the pushed messages, any read error
+// but the deadline exceeded means the connection is closed unexpectedly
+func (e *websocketExecutor) hold(conn *websocket.Conn) error {
+	if e.r.WsHoldTime <= 0 {
+		return nil
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(time.Duration(e.r.WsHoldTime) * time.Second)); err != nil {
+		return err
+	}
+	stop := stops[e.r.TaskId]
+	quit, exited := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(quit)
+		<-exited
+	}()
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(stopCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				if stop.True() {
+					// wake up the blocked read to finish holding
+					_ = conn.SetReadDeadline(time.Now())
+					return
+				}
+			}
+		}
+	}()
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// parseWsMessages parses the json array of scripted messages, string elements are sent as they are
+// and the other elements are sent as json
+func parseWsMessages(wsMessages string) ([]wsMessage, error) {
+	if len(wsMessages) == 0 {
+		return nil, nil
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(wsMessages), &elements); err != nil {
+		return nil, fmt.Errorf("websocket messages should be a json array: %w", err)
+	}
+	messages := make([]wsMessage, 0, len(elements))
+	for _, element := range elements {
+		var text string
+		if err := json.Unmarshal(element, &text); err == nil {
+			messages = append(messages, wsMessage{text: []byte(text)})
+			continue
+		}
+		message := wsMessage{text: element}
+		var object map[string]any
+		if err := json.Unmarshal(element, &object); err == nil {
+			message.object = object
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// wsCorrelationId returns the correlation field of the json reply
+func wsCorrelationId(reply []byte, field string) string {
+	var object map[string]any
+	if err := json.Unmarshal(reply, &object); err != nil {
+		return ""
+	}
+	id, _ := object[field].(string)
+	return id
+}
+
+// isWsDisconnect reports whether the error means the connection is closed by peer or broken
+func isWsDisconnect(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	return !websocket.IsCloseError(err, websocket.CloseNormalClosure)
+}
@@ -0,0 +1,123 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/peckfly/gopeck/internal/conf"
+	"github.com/peckfly/gopeck/internal/pkg/enums"
+	"github.com/peckfly/gopeck/pkg/atomicx"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// wsHandshakeDelay is the delay of the upgrade by the test server, so the handshake latency is measurable
+	wsHandshakeDelay = 20 * time.Millisecond
+	// wsReplyDelay is the delay between the pushed message and the reply of a json message
+	wsReplyDelay = 30 * time.Millisecond
+)
+
+// startWsServer starts the websocket server of the tests: /echo pushes a message of another id at once and
+// replies a json message after wsReplyDelay, and answers pong to the text messages, /drop drops the
+// connection after the first message, and /drop-hold drops it a moment after the handshake
+func startWsServer(t *testing.T) string {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(wsHandshakeDelay)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		switch r.URL.Path {
+		case "/drop":
+			_, _, _ = conn.ReadMessage()
+			_ = conn.UnderlyingConn().Close()
+		case "/drop-hold":
+			time.Sleep(50 * time.Millisecond)
+			_ = conn.UnderlyingConn().Close()
+		default:
+			for {
+				_, message, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				var object map[string]any
+				if json.Unmarshal(message, &object) != nil {
+					_ = conn.WriteMessage(websocket.TextMessage, []byte("pong"))
+					continue
+				}
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"pushed"}`))
+				time.Sleep(wsReplyDelay)
+				_ = conn.WriteMessage(websocket.TextMessage, message)
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestWebsocketExecutor(t *testing.T) {
+	url := startWsServer(t)
+	stops[3] = atomicx.ForAtomicBool(false)
+	defer delete(stops, 3)
+	tests := []struct {
+		name         string
+		r            *Requester
+		messages     int
+		minDuration  time.Duration
+		err          bool
+		disconnected bool
+	}{
+		// the pushed message and the pong are skipped until the reply of the same id
+		{name: "correlated", r: &Requester{Url: url + "/echo", WsMessages: `["ping", {"op": "sub"}, {"op": "echo"}]`, WsCorrelationField: "id"}, messages: 2, minDuration: wsReplyDelay},
+		// without correlation the next message is the reply
+		{name: "next message", r: &Requester{Url: url + "/echo", WsMessages: `["ping", "ping"]`}, messages: 2},
+		{name: "hold", r: &Requester{Url: url + "/echo", WsMessages: `[{"op": "sub"}]`, WsCorrelationField: "id", WsHoldTime: 1}, messages: 1, minDuration: wsReplyDelay},
+		{name: "dropped in round trip", r: &Requester{Url: url + "/drop", WsMessages: `["ping"]`}, err: true, disconnected: true},
+		{name: "dropped in hold", r: &Requester{Url: url + "/drop-hold", WsHoldTime: 1}, err: true, disconnected: true},
+		{name: "refused", r: &Requester{Url: "ws://127.0.0.1:1"}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.TaskId, tt.r.ProtocolType, tt.r.Timeout = 3, int32(enums.WebSocket), 1
+			e, err := newProtocolExecutor(context.Background(), &conf.WorkerStressConf{ErrorCutLength: 100}, tt.r)
+			assert.NoError(t, err)
+			result := e.Execute(context.Background())
+			e.Teardown(context.Background())
+			assert.Equal(t, tt.err, len(result.Err) > 0, result.Err)
+			assert.Equal(t, tt.disconnected, result.Disconnected)
+			assert.Len(t, result.MessageDurations, tt.messages)
+			for _, duration := range result.MessageDurations {
+				assert.Positive(t, duration)
+				assert.GreaterOrEqual(t, duration, tt.minDuration)
+			}
+			if tt.name == "refused" {
+				assert.Equal(t, noResponseStatus, result.StatusCode)
+				return
+			}
+			assert.Equal(t, http.StatusSwitchingProtocols, result.StatusCode)
+			assert.GreaterOrEqual(t, result.HandshakeDuration, wsHandshakeDelay)
+			assert.GreaterOrEqual(t, result.Duration, result.HandshakeDuration)
+		})
+	}
+}
+
+func TestParseWsMessages(t *testing.T) {
+	messages, err := parseWsMessages(`["ping", {"op": "sub"}, [1, 2]]`)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 3)
+	assert.Equal(t, "ping", string(messages[0].text))
+	assert.Nil(t, messages[0].object)
+	assert.Equal(t, map[string]any{"op": "sub"}, messages[1].object)
+	assert.Equal(t, "[1, 2]", string(messages[2].text))
+	assert.Nil(t, messages[2].object)
+	_, err = parseWsMessages(`{"op": "sub"}`)
+	assert.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/peckfly/gopeck/pkg/registry"
 	"go.uber.org/zap"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -46,6 +47,7 @@ func (s *PeckerServer) Init() error {
 		registry.WithServerMetadata(map[string]string{
 			consts.MaxConcurrencyNum: strconv.Itoa(s.conf.StressConf.MaxGoroutineNum),
 			consts.MaxRpsNum:         strconv.Itoa(s.conf.StressConf.MaxRpsNum),
+			consts.HttpEngines:       strings.Join([]string{consts.NetHttpEngine, consts.FastHttpEngine}, ","),
 		}))
 	v1.RegisterPeckServiceServer(grpcServer, s.peckService)
 	s.grpcServer = grpcServer
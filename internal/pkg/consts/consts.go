@@ -6,6 +6,15 @@ const (
 	Pecker            = "gopeck-pecker"
 	HttpScheme        = "http://"
 	HttpsScheme       = "https://"
+	WsScheme          = "ws://"
+	WssScheme         = "wss://"
+	TcpScheme         = "tcp://"
+	UdpScheme         = "udp://"
+	RedisScheme       = "redis://"
+	RedissScheme      = "rediss://"
 	MaxConcurrencyNum = "max_concurrency_num"
 	MaxRpsNum         = "max_rps_num"
+	HttpEngines       = "http_engines"
+	NetHttpEngine     = "net/http"
+	FastHttpEngine    = "fasthttp"
 )
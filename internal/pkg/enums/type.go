@@ -10,15 +10,57 @@ const (
 
 	Constants StressModeType = 1
 	Step      StressModeType = 2
+	Stage     StressModeType = 3
 
 	RedisSingle  = 0
 	RedisCluster = 1
+
+	Http      ProtocolType = 1
+	Grpc      ProtocolType = 2
+	WebSocket ProtocolType = 3
+	Tcp       ProtocolType = 4
+	Udp       ProtocolType = 5
+	Dns       ProtocolType = 6
+	Redis     ProtocolType = 7
+	Scenario  ProtocolType = 8
+
+	SseStream     ResponseStreamType = 1
+	ChunkedStream ResponseStreamType = 2
+
+	ConstantThinkTime    ThinkTimeType = 1
+	UniformThinkTime     ThinkTimeType = 2
+	ExponentialThinkTime ThinkTimeType = 3
+
+	ConstantPacer PacerType = 1
+	LinearPacer   PacerType = 2
+	PoissonPacer  PacerType = 3
+	SinePacer     PacerType = 4
+	SawtoothPacer PacerType = 5
+
+	LinearTransition  StageTransition = 1
+	InstantTransition StageTransition = 2
+
+	SkipInFlight  InFlightPolicy = 1
+	QueueInFlight InFlightPolicy = 2
+
+	DiscardBody  BodyMode = 1
+	SizeOnlyBody BodyMode = 2
+	HashBody     BodyMode = 3
+	BufferBody   BodyMode = 4
 )
 
 type (
 	StressType     int
 	StressModeType int
 	TaskStatus     int
+	ProtocolType   int
+
+	ResponseStreamType int
+	ThinkTimeType      int
+	PacerType          int
+	StageTransition    int
+	InFlightPolicy     int
+	BodyMode           int
 )
 
 var (
@@ -30,6 +72,18 @@ var (
 	systemSupportStressModeType = []StressModeType{
 		Constants,
 		Step,
+		Stage,
+	}
+
+	systemSupportProtocolType = []ProtocolType{
+		Http,
+		Grpc,
+		WebSocket,
+		Tcp,
+		Udp,
+		Dns,
+		Redis,
+		Scenario,
 	}
 )
 
@@ -50,3 +104,12 @@ func SupportStressModeType(stressModeType int) bool {
 	}
 	return false
 }
+
+func SupportProtocolType(protocolType int) bool {
+	for _, t := range systemSupportProtocolType {
+		if t == ProtocolType(protocolType) {
+			return true
+		}
+	}
+	return false
+}
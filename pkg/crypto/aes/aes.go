@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/base64"
+	"errors"
 )
 
 var (
@@ -20,7 +22,13 @@ func PKCS5Padding(plaintext []byte, blockSize int) []byte {
 
 func PKCS5UnPadding(origData []byte) []byte {
 	length := len(origData)
+	if length == 0 {
+		return origData
+	}
 	unfading := int(origData[length-1])
+	if unfading > length {
+		return nil
+	}
 	return origData[:(length - unfading)]
 }
 
@@ -53,6 +61,9 @@ func Decrypt(crypto, key []byte) ([]byte, error) {
 	}
 
 	blockSize := block.BlockSize()
+	if len(crypto)%blockSize != 0 {
+		return nil, errors.New("crypto data is not a multiple of the block size")
+	}
 	blockMode := cipher.NewCBCDecrypter(block, key[:blockSize])
 	origData := make([]byte, len(crypto))
 	blockMode.CryptBlocks(origData, crypto)
@@ -67,3 +78,54 @@ func DecryptFromBase64(data string, key []byte) ([]byte, error) {
 	}
 	return Decrypt(crypto, key)
 }
+
+// Seal encrypts and authenticates the data by AES-GCM with a random nonce, the nonce is prepended to the
+// sealed data, so that the same data is never encrypted to the same bytes
+func Seal(origData, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(origData)+gcm.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, origData, nil), nil
+}
+
+func SealToBase64(origData, key []byte) (string, error) {
+	sealed, err := Seal(origData, key)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts the data sealed by Seal, it fails if the data is tampered or sealed by another key
+func Open(sealed, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("sealed data is too short")
+	}
+	nonce, data := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, data, nil)
+}
+
+func OpenFromBase64(data string, key []byte) ([]byte, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	return Open(sealed, key)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -21,3 +21,32 @@ func TestAESEncrypt(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(data, result)
 }
+
+func TestAESSeal(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte("hello world")
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	first, err := SealToBase64(data, key)
+	assert.Nil(err)
+	second, err := SealToBase64(data, key)
+	assert.Nil(err)
+	// the random nonce makes every sealed data different
+	assert.NotEqual(first, second)
+
+	for _, sealed := range []string{first, second} {
+		result, err := OpenFromBase64(sealed, key)
+		assert.Nil(err)
+		assert.Equal(data, result)
+	}
+
+	_, err = OpenFromBase64(first, []byte("fedcba9876543210fedcba9876543210"))
+	assert.NotNil(err)
+	tampered := []byte(first)
+	tampered[len(tampered)-1] ^= 1
+	_, err = OpenFromBase64(string(tampered), key)
+	assert.NotNil(err)
+	_, err = OpenFromBase64("c2hvcnQ", key)
+	assert.NotNil(err)
+}
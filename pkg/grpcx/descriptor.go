@@ -0,0 +1,151 @@
+package grpcx
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"strings"
+)
+
+const (
+	Scheme       = "grpc://"
+	SecureScheme = "grpcs://"
+)
+
+var (
+	ErrInvalidMethodName = errors.New("grpc method name should be like package.Service/Method")
+	ErrMethodNotFound    = errors.New("grpc method not found")
+	ErrStreamingMethod   = errors.New("only unary grpc method is supported")
+)
+
+// ParseTarget trims the scheme of the url, grpcs:// means the target is served with tls
+func ParseTarget(url string) (target string, secure bool) {
+	if strings.HasPrefix(url, SecureScheme) {
+		return strings.TrimPrefix(url, SecureScheme), true
+	}
+	return strings.TrimPrefix(url, Scheme), false
+}
+
+// ParseMethodName splits a full method name into service name and method name,
+// both "package.Service/Method" and "/package.Service/Method" are accepted
+func ParseMethodName(fullMethod string) (service string, method string, err error) {
+	fullMethod = strings.TrimPrefix(strings.TrimSpace(fullMethod), "/")
+	pos := strings.LastIndex(fullMethod, "/")
+	if pos <= 0 || pos == len(fullMethod)-1 {
+		return "", "", ErrInvalidMethodName
+	}
+	return fullMethod[:pos], fullMethod[pos+1:], nil
+}
+
+// InvokePath returns the path used by grpc to invoke the method, like /package.Service/Method
+func InvokePath(md protoreflect.MethodDescriptor) string {
+	return "/" + string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+// DecodeFileDescriptorSet decodes a base64 encoded FileDescriptorSet
+func DecodeFileDescriptorSet(encoded string) (*protoregistry.Files, error) {
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("proto descriptor should be base64 encoded: %w", err)
+	}
+	return ParseFileDescriptorSet(b)
+}
+
+// ParseFileDescriptorSet parses the binary FileDescriptorSet, e.g. generated by protoc --descriptor_set_out --include_imports
+func ParseFileDescriptorSet(b []byte) (*protoregistry.Files, error) {
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(b, &fds); err != nil {
+		return nil, err
+	}
+	return protodesc.NewFiles(&fds)
+}
+
+// FindMethod finds the unary method by full method name in the files
+func FindMethod(files *protoregistry.Files, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := ParseMethodName(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, fullMethod)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a service", ErrMethodNotFound, serviceName)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(methodName))
+	if md == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, fullMethod)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("%w: %s", ErrStreamingMethod, fullMethod)
+	}
+	return md, nil
+}
+
+// ResolveMethodByReflection resolves the method descriptor with the server reflection service of the target
+func ResolveMethodByReflection(ctx context.Context, conn grpc.ClientConnInterface, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, _, err := ParseMethodName(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+	fdps := make(map[string]*descriptorpb.FileDescriptorProto)
+	pending := []*rpb.ServerReflectionRequest{{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: serviceName},
+	}}
+	for len(pending) > 0 {
+		req := pending[0]
+		pending = pending[1:]
+		if err = stream.Send(req); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, fmt.Errorf("server reflection error: %s", errResp.GetErrorMessage())
+		}
+		for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			var fdp descriptorpb.FileDescriptorProto
+			if err = proto.Unmarshal(b, &fdp); err != nil {
+				return nil, err
+			}
+			if _, ok := fdps[fdp.GetName()]; ok {
+				continue
+			}
+			fdps[fdp.GetName()] = &fdp
+			for _, dep := range fdp.GetDependency() {
+				if _, ok := fdps[dep]; ok {
+					continue
+				}
+				pending = append(pending, &rpb.ServerReflectionRequest{
+					MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+				})
+			}
+		}
+	}
+	fds := &descriptorpb.FileDescriptorSet{}
+	for _, fdp := range fdps {
+		fds.File = append(fds.File, fdp)
+	}
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, err
+	}
+	return FindMethod(files, fullMethod)
+}
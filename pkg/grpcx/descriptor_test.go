@@ -0,0 +1,60 @@
+package grpcx
+
+import (
+	"context"
+	"encoding/base64"
+	v1 "github.com/peckfly/gopeck/api/pecker/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"net"
+	"testing"
+)
+
+func TestParseMethodName(t *testing.T) {
+	service, method, err := ParseMethodName("/pecker.PeckService/peck")
+	assert.Nil(t, err)
+	assert.Equal(t, "pecker.PeckService", service)
+	assert.Equal(t, "peck", method)
+	_, _, err = ParseMethodName("pecker.PeckService")
+	assert.Equal(t, ErrInvalidMethodName, err)
+	_, _, err = ParseMethodName("pecker.PeckService/")
+	assert.Equal(t, ErrInvalidMethodName, err)
+}
+
+func TestFindMethod(t *testing.T) {
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(v1.File_api_pecker_v1_peck_proto),
+	}}
+	b, err := proto.Marshal(fds)
+	assert.Nil(t, err)
+	files, err := DecodeFileDescriptorSet(base64.StdEncoding.EncodeToString(b))
+	assert.Nil(t, err)
+	md, err := FindMethod(files, "pecker.PeckService/peck")
+	assert.Nil(t, err)
+	assert.Equal(t, "/pecker.PeckService/peck", InvokePath(md))
+	assert.Equal(t, "pecker.PeckRequest", string(md.Input().FullName()))
+	_, err = FindMethod(files, "pecker.PeckService/unknown")
+	assert.ErrorIs(t, err, ErrMethodNotFound)
+}
+
+func TestResolveMethodByReflection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := grpc.NewServer()
+	v1.RegisterPeckServiceServer(server, v1.UnimplementedPeckServiceServer{})
+	reflection.Register(server)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	md, err := ResolveMethodByReflection(context.Background(), conn, "pecker.PeckService/stop")
+	assert.Nil(t, err)
+	assert.Equal(t, "pecker.StopReply", string(md.Output().FullName()))
+}
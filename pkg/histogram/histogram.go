@@ -0,0 +1,149 @@
+package histogram
+
+import (
+	"math/bits"
+	"sort"
+	"time"
+)
+
+// subBucketBits is the bits of the linear sub buckets, the values below 1<<subBucketBits are exact and
+// every power of two range above is split into 1<<(subBucketBits-1) linear buckets, so the relative
+// error of a bucket is within 1/128
+const subBucketBits = 8
+
+// Histogram is a sparse log-linear histogram of microsecond values, the keys are the lowest values of
+// the buckets and the values are the counts, so that histograms are merged by adding the counts of the
+// same keys and the keys are still readable values in storage.
+type Histogram map[int64]int64
+
+// New returns an empty histogram
+func New() Histogram {
+	return make(Histogram)
+}
+
+// LowestEquivalentValue returns the lowest value of the bucket of the value
+func LowestEquivalentValue(v int64) int64 {
+	if v < 1<<subBucketBits {
+		return max(v, 0)
+	}
+	shift := bits.Len64(uint64(v)) - subBucketBits
+	return v >> shift << shift
+}
+
+// HighestEquivalentValue returns the highest value of the bucket of the value
+func HighestEquivalentValue(v int64) int64 {
+	if v < 1<<subBucketBits {
+		return max(v, 0)
+	}
+	shift := bits.Len64(uint64(v)) - subBucketBits
+	return v>>shift<<shift + 1<<shift - 1
+}
+
+// Record records the duration in microseconds
+func (h Histogram) Record(d time.Duration) {
+	h.RecordValues(d.Microseconds(), 1)
+}
+
+// RecordValues records the value n times
+func (h Histogram) RecordValues(v int64, n int64) {
+	h[LowestEquivalentValue(v)] += n
+}
+
+// Merge adds the counts of the other histogram
+func (h Histogram) Merge(o Histogram) {
+	for v, cnt := range o {
+		h[v] += cnt
+	}
+}
+
+// TotalCount returns the count of all recorded values
+func (h Histogram) TotalCount() int64 {
+	var total int64
+	for _, cnt := range h {
+		total += cnt
+	}
+	return total
+}
+
+// Min returns the lowest recorded value, it is the lowest value of its bucket
+func (h Histogram) Min() int64 {
+	first := true
+	var lowest int64
+	for v, cnt := range h {
+		if cnt > 0 && (first || v < lowest) {
+			lowest, first = v, false
+		}
+	}
+	return lowest
+}
+
+// Max returns the highest recorded value, it is the highest value of its bucket
+func (h Histogram) Max() int64 {
+	var highest int64
+	for v, cnt := range h {
+		if cnt > 0 {
+			highest = max(highest, HighestEquivalentValue(v))
+		}
+	}
+	return highest
+}
+
+// Mean returns the mean of the recorded values by the middle values of the buckets
+func (h Histogram) Mean() float64 {
+	var total int64
+	var sum float64
+	for v, cnt := range h {
+		if cnt <= 0 {
+			continue
+		}
+		total += cnt
+		sum += float64(v+HighestEquivalentValue(v)) / 2 * float64(cnt)
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / float64(total)
+}
+
+// ValueAtPercentile returns the value that the percentile of the recorded values are not greater than
+func (h Histogram) ValueAtPercentile(percentile float64) int64 {
+	return h.ValuesAtPercentiles([]float64{percentile})[0]
+}
+
+// ValuesAtPercentiles returns the values at the ascending percentiles, percentiles are in [0, 100]
+func (h Histogram) ValuesAtPercentiles(percentiles []float64) []int64 {
+	values := make([]int64, len(percentiles))
+	total := h.TotalCount()
+	if total == 0 {
+		return values
+	}
+	keys := h.sortedValues()
+	var cur int64
+	i := 0
+	for _, v := range keys {
+		cur += h[v]
+		for i < len(percentiles) && float64(cur)*100 >= percentiles[i]*float64(total) {
+			values[i] = HighestEquivalentValue(v)
+			i++
+		}
+		if i == len(percentiles) {
+			break
+		}
+	}
+	for ; i < len(percentiles); i++ {
+		values[i] = HighestEquivalentValue(keys[len(keys)-1])
+	}
+	return values
+}
+
+// sortedValues returns the recorded bucket values in ascending order
+func (h Histogram) sortedValues() []int64 {
+	keys := make([]int64, 0, len(h))
+	for v, cnt := range h {
+		if cnt > 0 {
+			keys = append(keys, v)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,72 @@
+package histogram
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestEquivalentValue(t *testing.T) {
+	for _, v := range []int64{0, 1, 255} {
+		assert.Equal(t, v, LowestEquivalentValue(v))
+		assert.Equal(t, v, HighestEquivalentValue(v))
+	}
+	assert.Equal(t, int64(0), LowestEquivalentValue(-3))
+	assert.Equal(t, int64(256), LowestEquivalentValue(257))
+	assert.Equal(t, int64(257), HighestEquivalentValue(256))
+	for v := int64(1); v < 1e9; v = v*3 + 1 {
+		low, high := LowestEquivalentValue(v), HighestEquivalentValue(v)
+		assert.LessOrEqual(t, low, v)
+		assert.GreaterOrEqual(t, high, v)
+		assert.LessOrEqual(t, float64(high-low), float64(low)/128, v)
+		assert.Equal(t, low, LowestEquivalentValue(high))
+	}
+}
+
+func TestRecord(t *testing.T) {
+	h := New()
+	h.Record(350 * time.Microsecond)
+	h.Record(350 * time.Microsecond)
+	h.Record(900 * time.Nanosecond)
+	h.Record(12 * time.Second)
+	assert.Equal(t, int64(4), h.TotalCount())
+	assert.Equal(t, int64(0), h.Min())
+	assert.Equal(t, int64(2), h[LowestEquivalentValue(350)])
+	assert.InDelta(t, 12e6, float64(h.Max()), 12e6/128)
+	assert.InDelta(t, (350+350+12e6)/4, h.Mean(), 12e6/128)
+}
+
+func TestMerge(t *testing.T) {
+	a, b := New(), New()
+	a.RecordValues(100, 3)
+	b.RecordValues(100, 2)
+	b.RecordValues(7000, 1)
+	a.Merge(b)
+	assert.Equal(t, int64(5), a[100])
+	assert.Equal(t, int64(6), a.TotalCount())
+	assert.Equal(t, int64(3), b.TotalCount())
+}
+
+func TestValuesAtPercentiles(t *testing.T) {
+	assert.Equal(t, []int64{0, 0}, New().ValuesAtPercentiles([]float64{50, 99}))
+
+	h := New()
+	for v := int64(1); v <= 100; v++ {
+		h.RecordValues(v, 1)
+	}
+	assert.Equal(t, []int64{1, 50, 90, 99, 100, 100}, h.ValuesAtPercentiles([]float64{0, 50, 90, 99, 99.9, 100}))
+
+	values := make([]int64, 100000)
+	h = New()
+	for i := range values {
+		values[i] = int64(rand.ExpFloat64() * 20000)
+		h.RecordValues(values[i], 1)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	for _, p := range []float64{50, 90, 99, 99.9} {
+		exact := values[int(p/100*float64(len(values)))-1]
+		assert.InDelta(t, float64(exact), float64(h.ValueAtPercentile(p)), float64(exact)/128+1, p)
+	}
+}
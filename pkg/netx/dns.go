@@ -0,0 +1,44 @@
+package netx
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const DefaultDnsPort = "53"
+
+// dnsQueryTypes holds the supported dns query types
+var dnsQueryTypes = map[string]dnsmessage.Type{
+	"A":    dnsmessage.TypeA,
+	"AAAA": dnsmessage.TypeAAAA,
+	"SRV":  dnsmessage.TypeSRV,
+	"TXT":  dnsmessage.TypeTXT,
+}
+
+// ParseDnsQueryType parses the query type name, empty name means A
+func ParseDnsQueryType(name string) (dnsmessage.Type, bool) {
+	if len(name) == 0 {
+		return dnsmessage.TypeA, true
+	}
+	t, ok := dnsQueryTypes[strings.ToUpper(name)]
+	return t, ok
+}
+
+// ParseDnsName parses the query name, the name is made fully qualified if it is not
+func ParseDnsName(name string) (dnsmessage.Name, error) {
+	name = strings.TrimSpace(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return dnsmessage.NewName(name)
+}
+
+// DnsServerAddress returns the address of the dns server with the default port if the port is missing
+func DnsServerAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), DefaultDnsPort)
+}
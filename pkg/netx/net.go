@@ -1,12 +1,19 @@
 package netx
 
 import (
+	"fmt"
 	"net"
 	"net/url"
+	"strings"
 	"time"
 )
 
 func Ping(urlStr string) error {
+	return PingMapped(urlStr, nil)
+}
+
+// PingMapped dials the host of the url, the mapped address is dialed if the host is in the host mapping
+func PingMapped(urlStr string, mapping HostMapping) error {
 	parsedURL, err := url.Parse(urlStr)
 	if err != nil {
 		return err
@@ -14,12 +21,15 @@ func Ping(urlStr string) error {
 	address := parsedURL.Hostname()
 	port := parsedURL.Port()
 	if len(port) == 0 {
-		if parsedURL.Scheme == "https" {
+		if parsedURL.Scheme == "https" || parsedURL.Scheme == "wss" {
 			port = "443"
 		} else {
 			port = "80"
 		}
 	}
+	if addrs := mapping.Lookup(address, port); len(addrs) > 0 {
+		address = addrs[0]
+	}
 	address = net.JoinHostPort(address, port)
 	conn, err := net.DialTimeout("tcp", address, 3*time.Second)
 	if err != nil {
@@ -28,3 +38,19 @@ func Ping(urlStr string) error {
 	defer conn.Close()
 	return nil
 }
+
+// ParseIPs parses the comma separated ip addresses
+func ParseIPs(s string) ([]net.IP, error) {
+	var ips []net.IP
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); len(item) == 0 {
+			continue
+		}
+		ip := net.ParseIP(strings.Trim(item, "[]"))
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip address: %s", item)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
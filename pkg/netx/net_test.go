@@ -28,3 +28,15 @@ func TestPing(t *testing.T) {
 		assert.Equal(t, tt.expect, err == nil)
 	}
 }
+
+func TestParseIPs(t *testing.T) {
+	ips, err := ParseIPs(" 10.0.0.1,[::1], ,10.0.0.2")
+	assert.NoError(t, err)
+	assert.Len(t, ips, 3)
+	assert.Equal(t, "::1", ips[1].String())
+	ips, err = ParseIPs("")
+	assert.NoError(t, err)
+	assert.Empty(t, ips)
+	_, err = ParseIPs("10.0.0.1,gopeck")
+	assert.Error(t, err)
+}
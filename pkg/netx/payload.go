@@ -0,0 +1,73 @@
+package netx
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type PayloadEncoding int
+
+const (
+	HexPayload    PayloadEncoding = 1
+	Base64Payload PayloadEncoding = 2
+)
+
+const (
+	placeholderStart = "{{"
+	placeholderEnd   = "}}"
+)
+
+var ErrUnclosedPlaceholder = errors.New("payload placeholder is not closed")
+
+// DecodePayload decodes the hex or base64 encoded payload, zero encoding means hex
+func DecodePayload(s string, encoding PayloadEncoding) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if encoding == Base64Payload {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("payload should be base64 encoded: %w", err)
+		}
+		return b, nil
+	}
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		return nil, fmt.Errorf("payload should be hex encoded: %w", err)
+	}
+	return b, nil
+}
+
+// BuildPayload builds the binary payload from the template, placeholders like {{name}} are replaced by the
+// param of the name. The literal parts and the param values are encoded with the same encoding and decoded
+// separately, e.g. "0001{{id}}ff" with id "2a" is 0x00 0x01 0x2a 0xff in hex encoding.
+func BuildPayload(template string, encoding PayloadEncoding, params map[string]string) ([]byte, error) {
+	var payload []byte
+	for len(template) > 0 {
+		start := strings.Index(template, placeholderStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(template[start:], placeholderEnd)
+		if end < 0 {
+			return nil, ErrUnclosedPlaceholder
+		}
+		literal, err := DecodePayload(template[:start], encoding)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSpace(template[start+len(placeholderStart) : start+end])
+		value, err := DecodePayload(params[name], encoding)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %w", name, err)
+		}
+		payload = append(append(payload, literal...), value...)
+		template = template[start+end+len(placeholderEnd):]
+	}
+	literal, err := DecodePayload(template, encoding)
+	if err != nil {
+		return nil, err
+	}
+	return append(payload, literal...), nil
+}
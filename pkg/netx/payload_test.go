@@ -0,0 +1,58 @@
+package netx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_buildPayload(t *testing.T) {
+	tests := []struct {
+		template string
+		encoding PayloadEncoding
+		params   map[string]string
+		expect   []byte
+		hasErr   bool
+	}{
+		{
+			template: "0001ff",
+			encoding: HexPayload,
+			expect:   []byte{0x00, 0x01, 0xff},
+		},
+		{
+			template: "00 01 {{id}} ff",
+			encoding: HexPayload,
+			params:   map[string]string{"id": "2a2b"},
+			expect:   []byte{0x00, 0x01, 0x2a, 0x2b, 0xff},
+		},
+		{
+			template: "AAE={{ id }}",
+			encoding: Base64Payload,
+			params:   map[string]string{"id": "/w=="},
+			expect:   []byte{0x00, 0x01, 0xff},
+		},
+		{
+			template: "{{a}}{{b}}",
+			params:   map[string]string{"a": "01", "b": "02"},
+			expect:   []byte{0x01, 0x02},
+		},
+		{
+			template: "00{{id",
+			encoding: HexPayload,
+			hasErr:   true,
+		},
+		{
+			template: "0g",
+			encoding: HexPayload,
+			hasErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		result, err := BuildPayload(tt.template, tt.encoding, tt.params)
+		if tt.hasErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expect, result)
+	}
+}
@@ -0,0 +1,140 @@
+package netx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// anyPort is the port of the mapping which matches every port
+const anyPort = "*"
+
+type (
+	// DialFunc dials the address like net.Dialer.DialContext
+	DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// HostMapping maps the host and port to the addresses dialed instead of the resolved ones
+	HostMapping map[string][]string
+
+	// Resolver dials the mapped addresses of the host mapping, the other hosts are resolved and cached
+	// for the ttl if it is positive, otherwise they are resolved by the dialer as usual
+	Resolver struct {
+		mapping HostMapping
+		ttl     time.Duration
+		mu      sync.RWMutex
+		cache   map[string]*resolved
+	}
+
+	resolved struct {
+		addrs  []string
+		expire time.Time
+	}
+)
+
+// ParseHostMapping parses the mappings like curl --resolve, one per line or separated by spaces, in the form
+// of host:port:addr[,addr]..., the port * matches every port and the ipv6 addresses are in brackets
+func ParseHostMapping(s string) (HostMapping, error) {
+	mapping := make(HostMapping)
+	for _, entry := range strings.Fields(s) {
+		host, rest, ok := strings.Cut(entry, ":")
+		port, addrs, ok2 := strings.Cut(rest, ":")
+		if !ok || !ok2 || len(host) == 0 || len(port) == 0 || len(addrs) == 0 {
+			return nil, fmt.Errorf("host mapping should be host:port:addr: %s", entry)
+		}
+		if port != anyPort {
+			if _, err := net.LookupPort("tcp", port); err != nil {
+				return nil, fmt.Errorf("invalid port of host mapping %s: %w", entry, err)
+			}
+		}
+		for _, addr := range strings.Split(addrs, ",") {
+			addr = strings.Trim(addr, "[]")
+			if net.ParseIP(addr) == nil {
+				return nil, fmt.Errorf("invalid address of host mapping %s: %s", entry, addr)
+			}
+			key := mappingKey(host, port)
+			mapping[key] = append(mapping[key], addr)
+		}
+	}
+	return mapping, nil
+}
+
+// Lookup returns the mapped addresses of the host and port, the mapping of the port is preferred to the one of any port
+func (m HostMapping) Lookup(host, port string) []string {
+	if addrs, ok := m[mappingKey(host, port)]; ok {
+		return addrs
+	}
+	return m[mappingKey(host, anyPort)]
+}
+
+func mappingKey(host, port string) string {
+	return strings.ToLower(host) + ":" + port
+}
+
+// NewResolver returns the resolver of the mapping and the dns cache ttl
+func NewResolver(mapping HostMapping, ttl time.Duration) *Resolver {
+	return &Resolver{mapping: mapping, ttl: ttl, cache: make(map[string]*resolved)}
+}
+
+// Enabled returns whether the resolver changes the addresses dialed
+func (r *Resolver) Enabled() bool {
+	return len(r.mapping) > 0 || r.ttl > 0
+}
+
+// DialContext wraps the dial function, the addresses of the host are dialed in order until one succeeds,
+// while the host of the url is kept, so that the host header and the sni are not changed
+func (r *Resolver) DialContext(dial DialFunc) DialFunc {
+	if !r.Enabled() {
+		return dial
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return dial(ctx, network, addr)
+		}
+		addrs, err := r.resolve(ctx, host, port)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return dial(ctx, network, addr)
+		}
+		var conn net.Conn
+		for _, a := range addrs {
+			if conn, err = dial(ctx, network, net.JoinHostPort(a, port)); err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// resolve returns the mapped or cached addresses of the host, nil means the host is dialed as it is
+func (r *Resolver) resolve(ctx context.Context, host, port string) ([]string, error) {
+	if addrs := r.mapping.Lookup(host, port); len(addrs) > 0 {
+		return addrs, nil
+	}
+	if r.ttl <= 0 || net.ParseIP(host) != nil {
+		return nil, nil
+	}
+	r.mu.RLock()
+	entry, ok := r.cache[host]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(entry.expire) {
+		return entry.addrs, nil
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+	r.mu.Lock()
+	r.cache[host] = &resolved{addrs: addrs, expire: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+	return addrs, nil
+}
@@ -0,0 +1,64 @@
+package netx
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostMapping(t *testing.T) {
+	mapping, err := ParseHostMapping("gopeck.io:443:10.0.0.1,[::1]\n  gopeck.io:*:10.0.0.2 Peck.io:80:127.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "::1"}, mapping.Lookup("gopeck.io", "443"))
+	assert.Equal(t, []string{"10.0.0.2"}, mapping.Lookup("gopeck.io", "8080"))
+	assert.Equal(t, []string{"127.0.0.1"}, mapping.Lookup("peck.io", "80"))
+	assert.Nil(t, mapping.Lookup("peck.io", "443"))
+
+	mapping, err = ParseHostMapping("")
+	assert.NoError(t, err)
+	assert.Empty(t, mapping)
+	for _, s := range []string{"gopeck.io", "gopeck.io:443", "gopeck.io:443:", "gopeck.io:port:10.0.0.1", "gopeck.io:443:gopeck.io"} {
+		_, err = ParseHostMapping(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	var dialed []string
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, &net.OpError{Op: "dial", Net: network, Err: net.UnknownNetworkError(addr)}
+	}
+	mapping, err := ParseHostMapping("gopeck.io:443:10.0.0.1,10.0.0.2")
+	assert.NoError(t, err)
+
+	// the mapped addresses are dialed in order until one succeeds
+	_, err = NewResolver(mapping, 0).DialContext(dial)(context.Background(), "tcp", "gopeck.io:443")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"10.0.0.1:443", "10.0.0.2:443"}, dialed)
+
+	// the other hosts are dialed as they are without the dns cache
+	dialed = nil
+	_, _ = NewResolver(mapping, 0).DialContext(dial)(context.Background(), "tcp", "localhost:80")
+	assert.Equal(t, []string{"localhost:80"}, dialed)
+
+	// the hosts are resolved once per ttl with the dns cache
+	resolver := NewResolver(nil, time.Minute)
+	assert.True(t, resolver.Enabled())
+	assert.False(t, NewResolver(nil, 0).Enabled())
+	dialed = nil
+	_, _ = resolver.DialContext(dial)(context.Background(), "tcp", "localhost:80")
+	assert.NotEmpty(t, dialed)
+	assert.NotContains(t, dialed, "localhost:80")
+	resolver.cache["localhost"].addrs = []string{"10.0.0.3"}
+	dialed = nil
+	_, _ = resolver.DialContext(dial)(context.Background(), "tcp", "localhost:80")
+	assert.Equal(t, []string{"10.0.0.3:80"}, dialed)
+	resolver.cache["localhost"].expire = time.Now()
+	dialed = nil
+	_, _ = resolver.DialContext(dial)(context.Background(), "tcp", "localhost:80")
+	assert.NotContains(t, dialed, "10.0.0.3:80")
+}
@@ -0,0 +1,88 @@
+package redisx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const pipelineName = "PIPELINE"
+
+var (
+	ErrEmptyCommands = errors.New("redis commands should not be empty")
+	ErrEmptyCommand  = errors.New("redis command should have a command or a pipeline")
+)
+
+// Command is a weighted redis command or pipeline, the arguments can be templates like user:{{id}}
+type Command struct {
+	// Name is the name reported with the latency, default to the command name or PIPELINE
+	Name     string     `json:"name"`
+	Command  []string   `json:"command"`
+	Pipeline [][]string `json:"pipeline"`
+	Weight   int        `json:"weight"`
+}
+
+// ParseCommands parses the json array of commands, the weight is at least 1
+func ParseCommands(s string) ([]*Command, error) {
+	var commands []*Command
+	if err := json.Unmarshal([]byte(s), &commands); err != nil {
+		return nil, fmt.Errorf("redis commands should be a json array: %w", err)
+	}
+	if len(commands) == 0 {
+		return nil, ErrEmptyCommands
+	}
+	for i, command := range commands {
+		if len(command.Command) == 0 && len(command.Pipeline) == 0 {
+			return nil, fmt.Errorf("%w: #%d", ErrEmptyCommand, i)
+		}
+		for _, args := range command.Pipeline {
+			if len(args) == 0 {
+				return nil, fmt.Errorf("%w: #%d has an empty pipeline command", ErrEmptyCommand, i)
+			}
+		}
+		if len(command.Name) == 0 {
+			if len(command.Pipeline) > 0 {
+				command.Name = pipelineName
+			} else {
+				command.Name = strings.ToUpper(command.Command[0])
+			}
+		}
+		command.Weight = max(command.Weight, 1)
+	}
+	return commands, nil
+}
+
+// Render fills the templates of the arguments with the params, a single command is rendered
+// as a pipeline of one command
+func (c *Command) Render(params map[string]string) [][]any {
+	var replacer *strings.Replacer
+	if len(params) > 0 {
+		oldnew := make([]string, 0, len(params)*2)
+		for k, v := range params {
+			oldnew = append(oldnew, "{{"+k+"}}", v)
+		}
+		replacer = strings.NewReplacer(oldnew...)
+	}
+	pipeline := c.Pipeline
+	if len(pipeline) == 0 {
+		pipeline = [][]string{c.Command}
+	}
+	rendered := make([][]any, 0, len(pipeline))
+	for _, args := range pipeline {
+		renderedArgs := make([]any, 0, len(args))
+		for _, arg := range args {
+			if replacer != nil {
+				arg = replacer.Replace(arg)
+			}
+			renderedArgs = append(renderedArgs, arg)
+		}
+		rendered = append(rendered, renderedArgs)
+	}
+	return rendered
+}
+
+// IsPipeline reports whether the command is a pipeline
+func (c *Command) IsPipeline() bool {
+	return len(c.Pipeline) > 0
+}
@@ -0,0 +1,42 @@
+package redisx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCommands(t *testing.T) {
+	commands, err := ParseCommands(`[
+		{"command": ["get", "user:{{id}}"], "weight": 3},
+		{"name": "set_user", "command": ["SET", "user:{{id}}", "{{name}}"]},
+		{"pipeline": [["HSET", "h:{{id}}", "f", "v"], ["EXPIRE", "h:{{id}}", "60"]], "weight": 2}
+	]`)
+	assert.NoError(t, err)
+	assert.Len(t, commands, 3)
+	assert.Equal(t, "GET", commands[0].Name)
+	assert.Equal(t, 3, commands[0].Weight)
+	assert.Equal(t, "set_user", commands[1].Name)
+	assert.Equal(t, 1, commands[1].Weight)
+	assert.Equal(t, "PIPELINE", commands[2].Name)
+	assert.True(t, commands[2].IsPipeline())
+
+	params := map[string]string{"id": "42", "name": "peck"}
+	assert.Equal(t, [][]any{{"get", "user:42"}}, commands[0].Render(params))
+	assert.Equal(t, [][]any{{"SET", "user:42", "peck"}}, commands[1].Render(params))
+	assert.Equal(t, [][]any{{"HSET", "h:42", "f", "v"}, {"EXPIRE", "h:42", "60"}}, commands[2].Render(params))
+	assert.Equal(t, [][]any{{"get", "user:{{id}}"}}, commands[0].Render(nil))
+}
+
+func TestParseCommandsError(t *testing.T) {
+	tests := []string{
+		``,
+		`{}`,
+		`[]`,
+		`[{"name": "x"}]`,
+		`[{"pipeline": [[]]}]`,
+	}
+	for _, tt := range tests {
+		_, err := ParseCommands(tt)
+		assert.Error(t, err, tt)
+	}
+}
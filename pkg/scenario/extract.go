@@ -0,0 +1,128 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	FromJson   = "json"
+	FromRegex  = "regex"
+	FromHeader = "header"
+)
+
+var ErrEmptyExtractVar = errors.New("extract variable name should not be empty")
+
+// Extractor extracts a variable from the response, by a json path like data.items.0.id,
+// by a regex whose first group or whole match is the value, or by a header name
+type Extractor struct {
+	Var  string `json:"var"`
+	From string `json:"from"`
+	Expr string `json:"expr"`
+
+	regex *regexp.Regexp
+	path  []string
+}
+
+func (e *Extractor) compile() error {
+	if len(e.Var) == 0 {
+		return ErrEmptyExtractVar
+	}
+	switch e.From {
+	case FromJson:
+		e.path = parseJsonPath(e.Expr)
+	case FromRegex:
+		regex, err := regexp.Compile(e.Expr)
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", e.Var, err)
+		}
+		e.regex = regex
+	case FromHeader:
+		if len(e.Expr) == 0 {
+			return fmt.Errorf("extract %s: header name should not be empty", e.Var)
+		}
+	default:
+		return fmt.Errorf("extract %s: not support extract from %s", e.Var, e.From)
+	}
+	return nil
+}
+
+// Extract extracts the variable from the response body and header, false means not found
+func (e *Extractor) Extract(body []byte, header http.Header) (string, bool) {
+	switch e.From {
+	case FromJson:
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			return "", false
+		}
+		return lookupJsonPath(v, e.path)
+	case FromRegex:
+		match := e.regex.FindSubmatch(body)
+		if match == nil {
+			return "", false
+		}
+		if len(match) > 1 {
+			return string(match[1]), true
+		}
+		return string(match[0]), true
+	case FromHeader:
+		values := header.Values(e.Expr)
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	}
+	return "", false
+}
+
+// parseJsonPath splits the path like $.data.items[0].id into data, items, 0, id
+func parseJsonPath(expr string) []string {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+	expr = strings.NewReplacer("[", ".", "]", "").Replace(expr)
+	if len(expr) == 0 {
+		return nil
+	}
+	return strings.Split(expr, ".")
+}
+
+// lookupJsonPath looks up the value of the path, objects and arrays are returned as json
+func lookupJsonPath(v any, path []string) (string, bool) {
+	for _, key := range path {
+		switch node := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = node[key]; !ok {
+				return "", false
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			v = node[i]
+		default:
+			return "", false
+		}
+	}
+	switch value := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
@@ -0,0 +1,72 @@
+package scenario
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestParseSteps(t *testing.T) {
+	steps, err := ParseSteps(`[
+		{"name": "login", "method": "post", "url": "/login", "body": "{\"user\":\"{{user}}\"}",
+			"extract": [{"var": "token", "from": "json", "expr": "$.data.token"}]},
+		{"name": "profile", "url": "https://other.test/profile?token={{token}}",
+			"extract": [{"var": "uid", "from": "regex", "expr": "uid=(\\d+)"}, {"var": "sid", "from": "header", "expr": "X-Session"}]}
+	]`, "http://api.test/v1/")
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "POST", steps[0].Method)
+	assert.Equal(t, "http://api.test/v1/login", steps[0].Url)
+	assert.Equal(t, "GET", steps[1].Method)
+	assert.Equal(t, "https://other.test/profile?token={{token}}", steps[1].Url)
+
+	tests := []string{
+		`[]`,
+		`[{"url": "/a"}]`,
+		`[{"name": "a"}, {"name": "a"}]`,
+		`[{"name": "a", "extract": [{"var": "x", "from": "xpath", "expr": "/a"}]}]`,
+		`[{"name": "a", "extract": [{"var": "x", "from": "regex", "expr": "("}]}]`,
+		`[{"name": "a", "extract": [{"from": "json", "expr": "a"}]}]`,
+	}
+	for _, tt := range tests {
+		_, err = ParseSteps(tt, "http://api.test")
+		assert.Error(t, err, tt)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	body := []byte(`{"data": {"token": "abc", "items": [{"id": 7}, {"id": 8.5}], "ok": true, "obj": {"a": 1}}, "msg": "uid=42"}`)
+	header := http.Header{"X-Session": []string{"s1"}}
+	tests := []struct {
+		extractor *Extractor
+		value     string
+		found     bool
+	}{
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "data.token"}, value: "abc", found: true},
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "$.data.items[1].id"}, value: "8.5", found: true},
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "data.items.0.id"}, value: "7", found: true},
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "data.ok"}, value: "true", found: true},
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "data.obj"}, value: `{"a":1}`, found: true},
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "data.missing"}},
+		{extractor: &Extractor{Var: "v", From: FromJson, Expr: "data.items.5"}},
+		{extractor: &Extractor{Var: "v", From: FromRegex, Expr: `uid=(\d+)`}, value: "42", found: true},
+		{extractor: &Extractor{Var: "v", From: FromRegex, Expr: `"token"`}, value: `"token"`, found: true},
+		{extractor: &Extractor{Var: "v", From: FromRegex, Expr: `gid=(\d+)`}},
+		{extractor: &Extractor{Var: "v", From: FromHeader, Expr: "x-session"}, value: "s1", found: true},
+		{extractor: &Extractor{Var: "v", From: FromHeader, Expr: "X-Missing"}},
+	}
+	for _, tt := range tests {
+		assert.NoError(t, tt.extractor.compile())
+		value, found := tt.extractor.Extract(body, header)
+		assert.Equal(t, tt.found, found, tt.extractor.Expr)
+		assert.Equal(t, tt.value, value, tt.extractor.Expr)
+	}
+}
+
+func TestRender(t *testing.T) {
+	vars := map[string]string{"token": "abc", "id": "1"}
+	assert.Equal(t, "/users/1?token=abc", Render("/users/{{id}}?token={{ token }}", vars))
+	assert.Equal(t, "/users/{{uid}}", Render("/users/{{uid}}", vars))
+	assert.Equal(t, "/users/{{id", Render("/users/{{id", vars))
+	assert.Equal(t, "plain", Render("plain", nil))
+}
@@ -0,0 +1,94 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrEmptySteps    = errors.New("scenario steps should not be empty")
+	ErrEmptyStepName = errors.New("scenario step name should not be empty")
+)
+
+// Step is one http request of the scenario, the url, headers, query and body can be templates
+// like {{token}} filled by the variables extracted from the previous steps
+type Step struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method"`
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Query   string            `json:"query"`
+	Body    string            `json:"body"`
+	Extract []*Extractor      `json:"extract"`
+}
+
+// ParseSteps parses the json array of steps, the relative urls of the steps are resolved against the base url
+func ParseSteps(s string, baseUrl string) ([]*Step, error) {
+	var steps []*Step
+	if err := json.Unmarshal([]byte(s), &steps); err != nil {
+		return nil, fmt.Errorf("scenario steps should be a json array: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, ErrEmptySteps
+	}
+	base, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(steps))
+	for i, step := range steps {
+		if len(step.Name) == 0 {
+			return nil, fmt.Errorf("%w: #%d", ErrEmptyStepName, i)
+		}
+		if _, ok := names[step.Name]; ok {
+			return nil, fmt.Errorf("duplicated scenario step name: %s", step.Name)
+		}
+		names[step.Name] = struct{}{}
+		if len(step.Method) == 0 {
+			step.Method = http.MethodGet
+		}
+		step.Method = strings.ToUpper(step.Method)
+		if !strings.HasPrefix(step.Url, "http://") && !strings.HasPrefix(step.Url, "https://") {
+			// templates in the relative path are kept as they are
+			step.Url = strings.TrimSuffix(base.String(), "/") + "/" + strings.TrimPrefix(step.Url, "/")
+		}
+		for _, extractor := range step.Extract {
+			if err = extractor.compile(); err != nil {
+				return nil, fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+	}
+	return steps, nil
+}
+
+// Render replaces the templates like {{name}} in s by the variables, unknown templates are kept
+func Render(s string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+		name := strings.TrimSpace(s[start+2 : start+end])
+		if value, ok := vars[name]; ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(s[start : start+end+2])
+		}
+		s = s[start+end+2:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
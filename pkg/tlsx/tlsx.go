@@ -0,0 +1,186 @@
+package tlsx
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/peckfly/gopeck/pkg/crypto/aes"
+	"strings"
+)
+
+// pemPrefix is the prefix of the plain pem material, the encrypted material is base64 without it
+const pemPrefix = "-----BEGIN"
+
+var (
+	ErrInvalidCa      = errors.New("no certificate found in the tls ca bundle")
+	ErrCertWithoutKey = errors.New("tls client certificate and key should be set together")
+	ErrNoSecret       = errors.New("tls secret is not configured, it is required by the tls pem material")
+	ErrPlainPem       = errors.New("tls pem material should be encrypted")
+)
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Options are the tls settings of a target, the pem material is encrypted by EncryptPem with the secret
+type Options struct {
+	// Secret is the secret shared by the admin and the peckers which the pem material is encrypted with
+	Secret string
+	// Verify verifies the server certificate by the system roots, it is implied by the ca bundle
+	Verify bool
+	Ca     string
+	Cert   string
+	Key    string
+	// ServerName overrides the sni and the name verified in the server certificate
+	ServerName string
+	// MinVersion and MaxVersion are like 1.2, CipherSuites and Alpn are comma separated
+	MinVersion   string
+	MaxVersion   string
+	CipherSuites string
+	Alpn         string
+}
+
+// EncryptPem encrypts the plain pem material with the secret, the empty or encrypted material is returned as it is
+func EncryptPem(pem, secret string) (string, error) {
+	if !isPlainPem(pem) {
+		return pem, nil
+	}
+	if len(secret) == 0 {
+		return "", ErrNoSecret
+	}
+	return aes.SealToBase64([]byte(pem), secretKey(secret))
+}
+
+// DecryptPem decrypts the pem material encrypted by EncryptPem with the secret, the empty material is returned
+// as it is, and the plain material is rejected, since the material is always encrypted once it is stored
+func DecryptPem(pem, secret string) (string, error) {
+	if len(pem) == 0 {
+		return pem, nil
+	}
+	if isPlainPem(pem) {
+		return "", ErrPlainPem
+	}
+	if len(secret) == 0 {
+		return "", ErrNoSecret
+	}
+	plain, err := aes.OpenFromBase64(pem, secretKey(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted tls pem material, or it is encrypted with another secret: %w", err)
+	}
+	if !isPlainPem(string(plain)) {
+		return "", errors.New("invalid encrypted pem material")
+	}
+	return string(plain), nil
+}
+
+// secretKey derives the aes-256 key of the secret
+func secretKey(secret string) []byte {
+	key := sha256.Sum256([]byte(secret))
+	return key[:]
+}
+
+func isPlainPem(pem string) bool {
+	return strings.HasPrefix(strings.TrimSpace(pem), pemPrefix)
+}
+
+// NewConfig builds the client tls config of the options, the server certificate is not verified unless
+// the verify option or the ca bundle is set
+func NewConfig(o *Options) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: !o.Verify && len(o.Ca) == 0,
+		ServerName:         o.ServerName,
+	}
+	if len(o.Ca) > 0 {
+		ca, err := DecryptPem(o.Ca, o.Secret)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return nil, ErrInvalidCa
+		}
+		config.RootCAs = pool
+	}
+	if len(o.Cert) > 0 || len(o.Key) > 0 {
+		if len(o.Cert) == 0 || len(o.Key) == 0 {
+			return nil, ErrCertWithoutKey
+		}
+		cert, err := DecryptPem(o.Cert, o.Secret)
+		if err != nil {
+			return nil, err
+		}
+		key, err := DecryptPem(o.Key, o.Secret)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+	var err error
+	if config.MinVersion, err = parseVersion(o.MinVersion); err != nil {
+		return nil, err
+	}
+	if config.MaxVersion, err = parseVersion(o.MaxVersion); err != nil {
+		return nil, err
+	}
+	if config.MinVersion > 0 && config.MaxVersion > 0 && config.MinVersion > config.MaxVersion {
+		return nil, fmt.Errorf("tls min version %s is greater than max version %s", o.MinVersion, o.MaxVersion)
+	}
+	if config.CipherSuites, err = parseCipherSuites(o.CipherSuites); err != nil {
+		return nil, err
+	}
+	config.NextProtos = splitList(o.Alpn)
+	return config, nil
+}
+
+// parseVersion parses the version like 1.2, zero means the default version
+func parseVersion(version string) (uint16, error) {
+	if len(version) == 0 {
+		return 0, nil
+	}
+	v, ok := versions[version]
+	if !ok {
+		return 0, fmt.Errorf("unknown tls version: %s", version)
+	}
+	return v, nil
+}
+
+// parseCipherSuites parses the comma separated cipher suite names like TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+// the cipher suites of tls 1.3 are not configurable
+func parseCipherSuites(names string) ([]uint16, error) {
+	list := splitList(names)
+	if len(list) == 0 {
+		return nil, nil
+	}
+	ids := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		ids[suite.Name] = suite.ID
+	}
+	suites := make([]uint16, 0, len(list))
+	for _, name := range list {
+		id, ok := ids[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tls cipher suite: %s", name)
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return list
+}
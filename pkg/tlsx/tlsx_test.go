@@ -0,0 +1,95 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPem(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	const secret = "gopeck-secret"
+	_, err := EncryptPem(ca, "")
+	assert.ErrorIs(t, err, ErrNoSecret)
+	encrypted, err := EncryptPem(ca, secret)
+	assert.NoError(t, err)
+	assert.NotEqual(t, ca, encrypted)
+	// the random iv makes every encryption different
+	another, err := EncryptPem(ca, secret)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, another)
+	// the encrypted material is not encrypted again
+	again, err := EncryptPem(encrypted, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, again)
+	plain, err := DecryptPem(encrypted, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, ca, plain)
+	empty, err := DecryptPem("", secret)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+	// the stored material is never plain
+	_, err = DecryptPem(ca, secret)
+	assert.ErrorIs(t, err, ErrPlainPem)
+	_, err = DecryptPem(encrypted, "")
+	assert.ErrorIs(t, err, ErrNoSecret)
+	_, err = DecryptPem(encrypted, "another-secret")
+	assert.Error(t, err)
+	_, err = DecryptPem("gopeck", secret)
+	assert.Error(t, err)
+
+	// the server is verified by the encrypted ca bundle
+	config, err := NewConfig(&Options{Ca: encrypted, Secret: secret})
+	assert.NoError(t, err)
+	assert.False(t, config.InsecureSkipVerify)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	resp, err := client.Get(srv.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	_, err = NewConfig(&Options{Ca: ca, Secret: secret})
+	assert.ErrorIs(t, err, ErrPlainPem)
+	config, err = NewConfig(&Options{Verify: true})
+	assert.NoError(t, err)
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	_, err = client.Get(srv.URL)
+	var verifyErr *tls.CertificateVerificationError
+	assert.ErrorAs(t, err, &verifyErr)
+}
+
+func TestNewConfig(t *testing.T) {
+	config, err := NewConfig(&Options{})
+	assert.NoError(t, err)
+	assert.True(t, config.InsecureSkipVerify)
+
+	config, err = NewConfig(&Options{
+		ServerName:   "gopeck.io",
+		MinVersion:   "1.2",
+		MaxVersion:   "1.3",
+		CipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_RSA_WITH_AES_128_CBC_SHA",
+		Alpn:         "h2,http/1.1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "gopeck.io", config.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_RSA_WITH_AES_128_CBC_SHA}, config.CipherSuites)
+	assert.Equal(t, []string{"h2", "http/1.1"}, config.NextProtos)
+
+	for _, o := range []*Options{
+		{MinVersion: "1.4"},
+		{MinVersion: "1.3", MaxVersion: "1.2"},
+		{CipherSuites: "TLS_GOPECK"},
+		{Ca: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----"},
+		{Cert: "-----BEGIN CERTIFICATE-----"},
+	} {
+		_, err = NewConfig(o)
+		assert.Error(t, err, o)
+	}
+}
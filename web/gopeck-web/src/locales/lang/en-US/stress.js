@@ -50,7 +50,7 @@ export default {
     'stress.task.result_check_distribution': 'Result Assertion Distribution',
 
     'stress.task.histogram.YAxisName': 'Quantity',
-    'stress.task.histogram.XAxisName': 'CostTime(ms)',
+    'stress.task.histogram.XAxisName': 'CostTime(µs)',
 
     'stress.task.resetParameters': 'Reset Parameters',
 
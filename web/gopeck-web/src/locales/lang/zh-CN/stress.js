@@ -50,7 +50,7 @@ export default {
     'stress.task.result_check_distribution': '结果断言分布',
 
     'stress.task.histogram.YAxisName': '数量',
-    'stress.task.histogram.XAxisName': '耗时(ms)',
+    'stress.task.histogram.XAxisName': '耗时(µs)',
 
     'stress.task.resetParameters': '重置参数',
 